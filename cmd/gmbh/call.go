@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gmbh-micro/gmbh"
+	"github.com/gmbh-micro/notify"
+	"github.com/gmbh-micro/rpc"
+	"github.com/gmbh-micro/rpc/intrigue"
+	"google.golang.org/grpc/metadata"
+)
+
+// callOptions are the flags of gmbh call
+type callOptions struct {
+	core        string
+	as          string
+	peerGroups  string
+	data        string
+	file        string
+	repeat      int
+	concurrency int
+	timeout     time.Duration
+}
+
+// callResult is the outcome of one data request
+type callResult struct {
+	data    json.RawMessage
+	err     string
+	elapsed time.Duration
+}
+
+// callCmd implements `gmbh call <service> <method>`. It registers with core as a temporary
+// service, sends the data request and prints the payload of the response as JSON. The exit
+// code is non-zero if any request failed.
+func callCmd(args []string) {
+
+	opts := callOptions{}
+	fs := flag.NewFlagSet("call", flag.ExitOnError)
+	endpoints := addEndpointFlags(fs)
+	fs.StringVar(&opts.as, "as", "gmbh-cli-"+strconv.Itoa(os.Getpid()), "the name to register with core and send the request as")
+	fs.StringVar(&opts.peerGroups, "peer-groups", "universal", "comma separated peer groups to register with")
+	fs.StringVar(&opts.data, "data", "", "the request data as a JSON object")
+	fs.StringVar(&opts.file, "file", "", "a file containing the request data as a JSON object, - for stdin")
+	fs.IntVar(&opts.repeat, "repeat", 1, "the number of requests to send")
+	fs.IntVar(&opts.concurrency, "concurrency", 1, "the number of requests to have in flight at once")
+	fs.DurationVar(&opts.timeout, "timeout", time.Second*5, "the timeout of each request")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: gmbh call <service> <method> [flags]\n")
+		fs.PrintDefaults()
+	}
+
+	pos := parseInterspersed(fs, args)
+	if len(pos) != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	service, method := pos[0], pos[1]
+	opts.core = endpoints.resolve().core
+	if opts.repeat < 1 {
+		opts.repeat = 1
+	}
+	if opts.concurrency < 1 {
+		opts.concurrency = 1
+	}
+
+	pload, err := callData(opts)
+	if err != nil {
+		notify.LnRedF("error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	client, con, err := rpc.DialCabal(opts.core)
+	if err != nil {
+		notify.LnRedF("could not contact gmbhCore; error=%s", err.Error())
+		os.Exit(1)
+	}
+	defer con.Close()
+
+	fp, err := callRegister(client, opts)
+	if err != nil {
+		notify.LnRedF("could not register with gmbhCore; error=%s", err.Error())
+		os.Exit(1)
+	}
+	defer callUnregister(client, opts, fp)
+
+	request := &intrigue.DataRequest{
+		Request: &intrigue.Request{
+			Tport: &intrigue.Transport{
+				Sender: opts.as,
+				Target: service,
+				Method: method,
+			},
+			Pload: pload,
+		},
+	}
+	send := func() callResult {
+		ctx, can := context.WithTimeout(context.Background(), opts.timeout)
+		defer can()
+		ctx = metadata.AppendToOutgoingContext(ctx, "sender", opts.as, "fingerprint", fp)
+
+		t := time.Now()
+		resp, err := client.Data(ctx, request)
+		r := callResult{elapsed: time.Since(t)}
+		switch {
+		case err != nil:
+			r.err = err.Error()
+		case resp.GetError() != "":
+			r.err = resp.GetError()
+		case resp.GetResponder().GetErr() != "":
+			r.err = resp.GetResponder().GetErr()
+		default:
+			r.data, err = gmbh.JSONFromPayload(resp.GetResponder().GetPload(), resp.GetResponder().GetCodec())
+			if err != nil {
+				r.err = "could not read response; " + err.Error()
+			}
+		}
+		return r
+	}
+
+	if opts.repeat == 1 {
+		r := send()
+		if r.err != "" {
+			notify.LnRedF("%s.%s failed; elapsed=%s; error=%s", service, method, r.elapsed, r.err)
+			callUnregister(client, opts, fp)
+			os.Exit(1)
+		}
+		printJSON(r.data)
+		fmt.Fprintf(os.Stderr, "%s.%s; elapsed=%s\n", service, method, r.elapsed)
+		return
+	}
+
+	results := make([]callResult, opts.repeat)
+	jobs := make(chan int)
+	wg := &sync.WaitGroup{}
+	start := time.Now()
+	for w := 0; w < opts.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = send()
+			}
+		}()
+	}
+	for i := 0; i < opts.repeat; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	failed := pprintCallStats(service, method, results, time.Since(start), opts.concurrency)
+	if failed != 0 {
+		callUnregister(client, opts, fp)
+		os.Exit(1)
+	}
+}
+
+// parseInterspersed parses the flags in args that may come before, between or after the
+// positional arguments, which are returned
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	pos := []string{}
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return pos
+		}
+		pos = append(pos, args[0])
+		args = args[1:]
+	}
+}
+
+// callData reads the request data from --data or --file into a payload
+func callData(opts callOptions) (*intrigue.Payload, error) {
+	var data []byte
+	switch {
+	case opts.data != "" && opts.file != "":
+		return nil, errors.New("only one of --data and --file may be used")
+	case opts.data != "":
+		data = []byte(opts.data)
+	case opts.file == "-":
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	case opts.file != "":
+		b, err := ioutil.ReadFile(opts.file)
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	}
+	p, err := gmbh.PayloadFromJSON(data)
+	if err != nil {
+		return nil, errors.New("data must be a JSON object")
+	}
+	return p, nil
+}
+
+// callRegister registers the cli with core and returns its fingerprint. The cli does not
+// serve any routes of its own.
+func callRegister(client intrigue.CabalClient, opts callOptions) (string, error) {
+	ctx, can := context.WithTimeout(context.Background(), opts.timeout)
+	defer can()
+
+	receipt, err := client.RegisterService(ctx, &intrigue.NewServiceRequest{
+		Service: &intrigue.NewService{
+			Name:       opts.as,
+			IsClient:   true,
+			PeerGroups: strings.Split(opts.peerGroups, ","),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if receipt.GetError() != "" {
+		return "", errors.New(receipt.GetError())
+	}
+	return receipt.GetServiceInfo().GetFingerprint(), nil
+}
+
+// callUnregister tells core that the cli has shut down
+func callUnregister(client intrigue.CabalClient, opts callOptions, fp string) {
+	ctx, can := context.WithTimeout(context.Background(), time.Second)
+	defer can()
+	ctx = metadata.AppendToOutgoingContext(ctx, "sender", opts.as, "fingerprint", fp)
+	client.UpdateRegistration(ctx, &intrigue.ServiceUpdate{
+		Request: "shutdown.notif",
+		Message: opts.as,
+	})
+}
+
+// printJSON prints data indented to stdout
+func printJSON(data json.RawMessage) {
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		out = data
+	}
+	fmt.Println(string(out))
+}
+
+// pprintCallStats prints the latencies and errors of repeated requests and returns the number
+// that failed
+func pprintCallStats(service, method string, results []callResult, total time.Duration, concurrency int) int {
+
+	latencies := make([]time.Duration, 0, len(results))
+	errs := make(map[string]int)
+	failed := 0
+	var sum time.Duration
+	for _, r := range results {
+		if r.err != "" {
+			errs[r.err]++
+			failed++
+			continue
+		}
+		latencies = append(latencies, r.elapsed)
+		sum += r.elapsed
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	percentile := func(p float64) time.Duration {
+		if len(latencies) == 0 {
+			return 0
+		}
+		return latencies[int(float64(len(latencies)-1)*p)]
+	}
+
+	fmt.Printf("%s.%s\n", service, method)
+	fmt.Printf("  requests     %d (concurrency %d)\n", len(results), concurrency)
+	fmt.Printf("  succeeded    %d\n", len(latencies))
+	fmt.Printf("  failed       %d\n", failed)
+	fmt.Printf("  total        %s\n", total)
+	fmt.Printf("  rate         %.1f/s\n", float64(len(results))/total.Seconds())
+	if len(latencies) != 0 {
+		fmt.Printf("  latency      min=%s avg=%s p50=%s p95=%s p99=%s max=%s\n",
+			latencies[0],
+			sum/time.Duration(len(latencies)),
+			percentile(0.50),
+			percentile(0.95),
+			percentile(0.99),
+			latencies[len(latencies)-1],
+		)
+	}
+	for e, n := range errs {
+		notify.LnRedF("  error        %dx %s", n, e)
+	}
+	return failed
+}
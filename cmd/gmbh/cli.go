@@ -1,11 +1,9 @@
 package main
 
 import (
-	"fmt"
 	"strings"
 	"time"
 
-	"github.com/gmbh-micro/config"
 	"github.com/gmbh-micro/notify"
 	"github.com/gmbh-micro/rpc"
 	"github.com/gmbh-micro/rpc/intrigue"
@@ -13,54 +11,79 @@ import (
 	"google.golang.org/grpc/codes"
 )
 
-func listAll() {
+// listAll prints the remotes from procm merged with the services from core and returns the
+// exit code
+func listAll(ep *endpoints, output string) int {
 
 	remotes := []*intrigue.ProcessManager{}
+	procmErr := ""
 	{
-		client, ctx, can, err := rpc.GetControlRequest(config.DefaultSystemProcm.Address, time.Second*2)
+		client, ctx, can, err := rpc.GetControlRequest(ep.procm, time.Second*2)
 		if err != nil {
-			notify.LnRedF("error: " + err.Error())
-		}
-		defer can()
-
-		request := intrigue.Action{
-			Request: "summary.all",
-		}
-		resp, err := client.Summary(ctx, &request)
-		if err != nil {
-			notify.LnBlueF("Could not contact gmbhProcm; error=%s", err.Error())
-			return
-		}
-		if resp.Error != "" {
-			notify.LnBlueF("Could not contact gmbhCore; error=%s", resp.Error)
+			procmErr = err.Error()
+		} else {
+			defer can()
+			resp, err := client.Summary(ctx, &intrigue.Action{
+				Request: "summary.all",
+			})
+			if err != nil {
+				procmErr = handleErr(err)
+			} else {
+				procmErr = resp.GetError()
+				remotes = resp.GetRemotes()
+			}
 		}
-		remotes = resp.GetRemotes()
 	}
 	services := []*intrigue.CoreService{}
+	coreErr := ""
 	{
-		client, ctx, can, err := rpc.GetCabalRequest(config.DefaultSystemCore.Address, time.Second*2)
+		client, ctx, can, err := rpc.GetCabalRequest(ep.core, time.Second*2)
 		if err != nil {
-			notify.LnRedF("error: " + err.Error())
+			coreErr = err.Error()
+		} else {
+			defer can()
+			resp, err := client.Summary(ctx, &intrigue.Action{
+				Request: "request.info.all",
+			})
+			if err != nil {
+				coreErr = handleErr(err)
+			} else if resp.GetServices() == nil {
+				coreErr = resp.GetError()
+			} else {
+				services = resp.GetServices()
+			}
 		}
-		defer can()
+	}
 
-		request := &intrigue.Action{
-			Request: "request.info.all",
+	if output == outputJSON || output == outputYAML {
+		out := buildListOutput(remotes, services)
+		out.ProcmError = procmErr
+		out.CoreError = coreErr
+		if err := writeOutput(output, out); err != nil {
+			notify.LnRedF("error: " + err.Error())
+			return exitError
 		}
-		resp, err := client.Summary(ctx, request)
-		if err != nil {
-			notify.LnBlueF("Could not contact gmbhCore; error=%s", err.Error())
+	} else {
+		if procmErr != "" {
+			notify.LnBlueF("Could not contact gmbhProcm; error=%s", procmErr)
 		}
-		if resp.GetServices() == nil {
-			notify.LnBlueF("Could not contact gmbhCore; error=%s", resp.Error)
+		if coreErr != "" {
+			notify.LnBlueF("Could not contact gmbhCore; error=%s", coreErr)
 		}
-		services = resp.GetServices()
+		pprintListAll(remotes, services)
 	}
-	pprintListAll(remotes, services)
+
+	if procmErr != "" || coreErr != "" {
+		return exitUnreachable
+	}
+	if anyFailed(remotes) {
+		return exitServiceFailed
+	}
+	return 0
 }
 
-func runReport() {
-	client, ctx, can, err := rpc.GetControlRequest(config.DefaultSystemProcm.Address, time.Second)
+func runReport(ep *endpoints) {
+	client, ctx, can, err := rpc.GetControlRequest(ep.procm, time.Second)
 	if err != nil {
 		notify.LnBlueF("error: " + err.Error())
 	}
@@ -79,34 +102,41 @@ func runReport() {
 	pprintListOne(reply.GetRemotes())
 }
 
-func restartAll() {
-	client, ctx, can, err := rpc.GetControlRequest(config.DefaultSystemProcm.Address, time.Second)
+// restartAll asks procm to restart every service and returns the exit code
+func restartAll(ep *endpoints, output string) int {
+	client, ctx, can, err := rpc.GetControlRequest(ep.procm, time.Second)
 	if err != nil {
-		notify.LnRedF("error: " + err.Error())
+		return actionResult(output, actionOutput{Request: "restart.all", Error: err.Error()}, exitUnreachable)
 	}
 	defer can()
 
 	request := &intrigue.Action{
 		Request: "restart.all",
 	}
-	_, err = client.RestartService(ctx, request)
+	reply, err := client.RestartService(ctx, request)
 	if err != nil {
-		notify.LnRedF("error: " + err.Error())
-		return
+		return actionResult(output, actionOutput{Request: "restart.all", Error: handleErr(err)}, exitUnreachable)
 	}
+	return actionResult(output, actionOutput{
+		Request: "restart.all",
+		Message: reply.GetMessage(),
+		Error:   reply.GetError(),
+	}, exitError)
 }
 
-func listOne(id string) {
-	client, ctx, can, err := rpc.GetControlRequest(config.DefaultSystemProcm.Address, time.Second*5)
+// listOne prints the service with id, as remote-service, and returns the exit code
+func listOne(ep *endpoints, id, output string) int {
+	client, ctx, can, err := rpc.GetControlRequest(ep.procm, time.Second*5)
 	if err != nil {
 		notify.LnRedF("error: " + err.Error())
+		return exitUnreachable
 	}
 	defer can()
 
 	splitID := strings.Split(id, "-")
 	if len(splitID) != 2 {
 		notify.LnRedF("could not parse id")
-		return
+		return exitUsage
 	}
 
 	request := &intrigue.Action{
@@ -117,28 +147,46 @@ func listOne(id string) {
 	reply, err := client.Summary(ctx, request)
 	if err != nil {
 		notify.LnRedF(handleErr(err))
-		return
+		return exitUnreachable
 	}
 
 	if reply.GetError() != "" {
-		notify.LnRedF("could not find service with id: " + id)
-		notify.LnRedF("report from core=" + reply.GetError())
-		return
+		if output == outputJSON || output == outputYAML {
+			writeOutput(output, listOutput{Remotes: []remoteOutput{}, ProcmError: reply.GetError()})
+		} else {
+			notify.LnRedF("could not find service with id: " + id)
+			notify.LnRedF("report from core=" + reply.GetError())
+		}
+		return exitError
 	}
-	pprintListOne(reply.GetRemotes())
+
+	if output == outputJSON || output == outputYAML {
+		if err := writeOutput(output, buildListOutput(reply.GetRemotes(), nil)); err != nil {
+			notify.LnRedF("error: " + err.Error())
+			return exitError
+		}
+	} else {
+		pprintListOne(reply.GetRemotes())
+	}
+	if anyFailed(reply.GetRemotes()) {
+		return exitServiceFailed
+	}
+	return 0
 }
 
-func restartOne(id string) {
-	client, ctx, can, err := rpc.GetControlRequest(config.DefaultSystemProcm.Address, time.Second*20)
+// restartOne asks procm to restart the service with id, as remote-service, and returns the
+// exit code
+func restartOne(ep *endpoints, id, output string) int {
+	client, ctx, can, err := rpc.GetControlRequest(ep.procm, time.Second*20)
 	if err != nil {
-		notify.LnRedF("client error: " + err.Error())
+		return actionResult(output, actionOutput{Request: "restart.one", Target: id, Error: err.Error()}, exitUnreachable)
 	}
 	defer can()
 
 	splitID := strings.Split(id, "-")
 	if len(splitID) != 2 {
 		notify.LnRedF("could not parse id")
-		return
+		return exitUsage
 	}
 
 	request := &intrigue.Action{
@@ -148,32 +196,57 @@ func restartOne(id string) {
 	}
 	reply, err := client.RestartService(ctx, request)
 	if err != nil {
-		fmt.Println(err)
-		notify.LnRedF("send error: " + err.Error())
-		return
-	}
-
-	notify.LnBlueF(reply.String())
+		return actionResult(output, actionOutput{Request: "restart.one", Target: id, Error: handleErr(err)}, exitUnreachable)
+	}
+	return actionResult(output, actionOutput{
+		Request: "restart.one",
+		Target:  id,
+		Message: reply.GetMessage(),
+		Error:   reply.GetError(),
+	}, exitError)
 }
 
-func shutdown() {
-	client, ctx, can, err := rpc.GetControlRequest(config.DefaultSystemProcm.Address, time.Second)
+// shutdown asks procm to shut down gmbh and returns the exit code
+func shutdown(ep *endpoints, output string) int {
+	client, ctx, can, err := rpc.GetControlRequest(ep.procm, time.Second)
 	if err != nil {
-		notify.LnRedF("error: " + err.Error())
+		return actionResult(output, actionOutput{Request: "shutdown", Error: err.Error()}, exitUnreachable)
 	}
 	defer can()
 
 	reply, err := client.StopServer(ctx, &intrigue.EmptyRequest{})
 	if err != nil {
-		notify.LnRedF("error: " + err.Error())
-		return
+		return actionResult(output, actionOutput{Request: "shutdown", Error: handleErr(err)}, exitUnreachable)
+	}
+	return actionResult(output, actionOutput{
+		Request: "shutdown",
+		Message: reply.GetMessage(),
+		Error:   reply.GetError(),
+	}, exitError)
+}
+
+// actionResult prints the outcome of a request to procm and returns the exit code, code if
+// there was an error
+func actionResult(output string, out actionOutput, code int) int {
+	if output == outputJSON || output == outputYAML {
+		if err := writeOutput(output, out); err != nil {
+			notify.LnRedF("error: " + err.Error())
+			return exitError
+		}
+	} else if out.Error != "" {
+		notify.LnRedF("error: " + out.Error)
+	} else {
+		notify.LnBlueF(out.Message)
+	}
+	if out.Error != "" {
+		return code
 	}
-	notify.LnBlueF(reply.String())
+	return 0
 }
 
 func handleErr(err error) string {
 	if grpc.Code(err) == codes.Unavailable {
-		return "could not connect to gmbhCore"
+		return "could not connect"
 	}
-	return "unsupported error code"
+	return err.Error()
 }
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gmbh-micro/config"
+	"github.com/gmbh-micro/notify"
+)
+
+// endpoints are the addresses of the core and procm that the cli talks to. adminToken is the
+// admin token of core when it was read from a project config.
+type endpoints struct {
+	core       string
+	procm      string
+	adminToken string
+}
+
+// endpointFlags are the flags that choose the endpoints
+type endpointFlags struct {
+	config  *string
+	context *string
+	core    *string
+	procm   *string
+}
+
+// addEndpointFlags adds --config, --context, --core and --procm to fs
+func addEndpointFlags(fs *flag.FlagSet) *endpointFlags {
+	return &endpointFlags{
+		config:  fs.String("config", "", "a gmbh project config file to read the core and procm addresses from"),
+		context: fs.String("context", "", "the context to use instead of the current context"),
+		core:    fs.String("core", "", "the address of gmbhCore, overrides the context and config"),
+		procm:   fs.String("procm", "", "the address of gmbhProcm, overrides the context and config"),
+	}
+}
+
+// resolve returns the endpoints or exits if they cannot be worked out
+func (f *endpointFlags) resolve() *endpoints {
+	ep, err := resolveEndpoints(*f.config, *f.context, *f.core, *f.procm)
+	if err != nil {
+		notify.LnRedF("error: %s", err.Error())
+		os.Exit(exitUsage)
+	}
+	return ep
+}
+
+// resolveEndpoints starts from the defaults and applies, each overriding the last, the named
+// or current context, the project config file and the addresses given directly
+func resolveEndpoints(configPath, context, core, procm string) (*endpoints, error) {
+
+	ep := &endpoints{
+		core:  config.DefaultSystemCore.Address,
+		procm: config.DefaultSystemProcm.Address,
+	}
+
+	cli, err := config.ParseCLIConfig(config.CLIConfigPath())
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s; %s", config.CLIConfigPath(), err.Error())
+	}
+	if context == "" {
+		context = cli.CurrentContext
+	}
+	if context != "" {
+		ctx, err := cli.Lookup(context)
+		if err != nil {
+			return nil, err
+		}
+		if ctx.Config != "" {
+			if err := ep.fromProject(ctx.Config); err != nil {
+				return nil, err
+			}
+		}
+		if ctx.Core != "" {
+			ep.core = ctx.Core
+		}
+		if ctx.Procm != "" {
+			ep.procm = ctx.Procm
+		}
+	}
+
+	if configPath != "" {
+		if err := ep.fromProject(configPath); err != nil {
+			return nil, err
+		}
+	}
+	if core != "" {
+		ep.core = core
+	}
+	if procm != "" {
+		ep.procm = procm
+	}
+	return ep, nil
+}
+
+// fromProject sets the addresses from the core and procm sections of a project config
+func (ep *endpoints) fromProject(path string) error {
+	conf, err := config.ParseSystemConfig(path)
+	if err != nil {
+		return fmt.Errorf("could not parse %s; %s", path, err.Error())
+	}
+	if conf.Core != nil {
+		ep.core = conf.Core.Address
+		ep.adminToken = conf.Core.AdminToken
+	}
+	if conf.Procm != nil {
+		ep.procm = conf.Procm.Address
+	}
+	return nil
+}
+
+// contextCmd implements `gmbh context`, which manages the named contexts in the user config
+func contextCmd(args []string) {
+
+	usage := func() {
+		fmt.Fprintf(os.Stderr, `usage:
+  gmbh context list                 list the contexts, the current one is marked with *
+  gmbh context current              print the name of the current context
+  gmbh context use <name>           make name the current context
+  gmbh context set <name> [flags]   add or change a context
+  gmbh context delete <name>        remove a context
+`)
+	}
+	if len(args) == 0 {
+		usage()
+		os.Exit(exitUsage)
+	}
+
+	path := config.CLIConfigPath()
+	cli, err := config.ParseCLIConfig(path)
+	if err != nil {
+		notify.LnRedF("could not parse %s; error=%s", path, err.Error())
+		os.Exit(exitError)
+	}
+
+	write := func() {
+		if err := cli.Write(path); err != nil {
+			notify.LnRedF("could not write %s; error=%s", path, err.Error())
+			os.Exit(exitError)
+		}
+	}
+
+	switch args[0] {
+	case "list":
+		fmt.Printf("  %-16s %-24s %-24s %s\n", "NAME", "CORE", "PROCM", "CONFIG")
+		for _, ctx := range cli.Contexts {
+			current := " "
+			if ctx.Name == cli.CurrentContext {
+				current = "*"
+			}
+			fmt.Printf("%s %-16s %-24s %-24s %s\n", current, ctx.Name, ctx.Core, ctx.Procm, ctx.Config)
+		}
+
+	case "current":
+		if cli.CurrentContext == "" {
+			notify.LnRedF("no current context; using the defaults")
+			os.Exit(exitError)
+		}
+		fmt.Println(cli.CurrentContext)
+
+	case "use":
+		if len(args) != 2 {
+			usage()
+			os.Exit(exitUsage)
+		}
+		if _, err := cli.Lookup(args[1]); err != nil {
+			notify.LnRedF("%s", err.Error())
+			os.Exit(exitError)
+		}
+		cli.CurrentContext = args[1]
+		write()
+		fmt.Printf("using context %s\n", args[1])
+
+	case "set":
+		fs := flag.NewFlagSet("context set", flag.ExitOnError)
+		core := fs.String("core", "", "the address of gmbhCore")
+		procm := fs.String("procm", "", "the address of gmbhProcm")
+		conf := fs.String("config", "", "a gmbh project config file to read the addresses from")
+		pos := parseInterspersed(fs, args[1:])
+		if len(pos) != 1 {
+			usage()
+			os.Exit(exitUsage)
+		}
+
+		ctx, err := cli.Lookup(pos[0])
+		if err != nil {
+			ctx = &config.CLIContext{Name: pos[0]}
+		}
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "core":
+				ctx.Core = *core
+			case "procm":
+				ctx.Procm = *procm
+			case "config":
+				ctx.Config = *conf
+				if abs, err := filepath.Abs(*conf); err == nil && *conf != "" {
+					ctx.Config = abs
+				}
+			}
+		})
+		cli.Set(ctx)
+		if cli.CurrentContext == "" {
+			cli.CurrentContext = ctx.Name
+		}
+		write()
+		fmt.Printf("context %s set\n", ctx.Name)
+
+	case "delete":
+		if len(args) != 2 {
+			usage()
+			os.Exit(exitUsage)
+		}
+		if err := cli.Remove(args[1]); err != nil {
+			notify.LnRedF("%s", err.Error())
+			os.Exit(exitError)
+		}
+		write()
+		fmt.Printf("context %s deleted\n", args[1])
+
+	default:
+		usage()
+		os.Exit(exitUsage)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gmbh-micro/notify"
+	"github.com/gmbh-micro/rpc"
+	"github.com/gmbh-micro/rpc/intrigue"
+)
+
+// logSource is one log file on a remote
+type logSource struct {
+	label    string
+	remoteID string
+
+	// target is the id of the service on its remote, empty for the log of the remote
+	target string
+}
+
+// logPrefixColors are cycled through to tell sources apart
+var logPrefixColors = []color.Attribute{
+	color.FgCyan,
+	color.FgGreen,
+	color.FgMagenta,
+	color.FgYellow,
+	color.FgBlue,
+	color.FgRed,
+}
+
+// logsCmd implements `gmbh logs <service|remote>...`. The logs are read by the remote that
+// runs the service and streamed through procm, so this works the same for remotes in
+// containers. With more than one source each line is prefixed with the name of its service.
+func logsCmd(args []string) {
+
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	endpoints := addEndpointFlags(fs)
+	follow := fs.Bool("f", false, "keep printing lines as they are written")
+	since := fs.String("since", "", "skip lines from before a duration ago (10m) or an RFC3339 time")
+	grep := fs.String("grep", "", "only print lines matching this regular expression")
+	tail := fs.Int("tail", 0, "only print this many lines from the end of each log, 0 for all")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: gmbh logs <service|remote>... [flags]\n")
+		fs.PrintDefaults()
+	}
+
+	names := parseInterspersed(fs, args)
+	if len(names) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	procm := endpoints.resolve().procm
+
+	request := &intrigue.LogRequest{
+		Follow: *follow,
+		Grep:   *grep,
+		Tail:   int32(*tail),
+	}
+	if *grep != "" {
+		if _, err := regexp.Compile(*grep); err != nil {
+			notify.LnRedF("invalid --grep; error=%s", err.Error())
+			os.Exit(2)
+		}
+	}
+	if *since != "" {
+		t, err := parseSince(*since)
+		if err != nil {
+			notify.LnRedF("invalid --since; expected a duration or an RFC3339 time")
+			os.Exit(2)
+		}
+		request.Since = t.Format(time.RFC3339)
+	}
+
+	sources, err := resolveLogSources(procm, names)
+	if err != nil {
+		notify.LnRedF("%s", err.Error())
+		os.Exit(1)
+	}
+
+	client, con, err := rpc.DialControl(procm)
+	if err != nil {
+		notify.LnRedF("could not contact gmbhProcm; error=%s", err.Error())
+		os.Exit(1)
+	}
+	defer con.Close()
+
+	width := 0
+	for _, s := range sources {
+		if len(s.label) > width {
+			width = len(s.label)
+		}
+	}
+
+	// lines are printed under a lock so that lines from different sources do not mix
+	mu := &sync.Mutex{}
+	printLine := func(i int, line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(sources) == 1 {
+			fmt.Println(line)
+			return
+		}
+		prefix := color.New(logPrefixColors[i%len(logPrefixColors)]).Sprintf("%-*s |", width, sources[i].label)
+		fmt.Println(prefix, line)
+	}
+
+	failed := false
+	wg := &sync.WaitGroup{}
+	for i, s := range sources {
+		wg.Add(1)
+		go func(i int, s logSource) {
+			defer wg.Done()
+			err := streamLog(context.Background(), client, s, request, func(line string) { printLine(i, line) })
+			if err != nil {
+				mu.Lock()
+				failed = true
+				notify.LnRedF("%s: %s", s.label, err.Error())
+				mu.Unlock()
+			}
+		}(i, s)
+	}
+	wg.Wait()
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// streamLog calls line with each line of the log of s until the log ends or ctx is done
+func streamLog(ctx context.Context, client intrigue.ControlClient, s logSource, request *intrigue.LogRequest, line func(string)) error {
+
+	req := *request
+	req.RemoteID = s.remoteID
+	req.Target = s.target
+
+	logs, err := client.Logs(ctx, &req)
+	if err != nil {
+		return err
+	}
+	for {
+		l, err := logs.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if l.GetError() != "" {
+			return fmt.Errorf("%s", l.GetError())
+		}
+		line(l.GetLine())
+	}
+}
+
+// resolveLogSources finds the logs of names in the summary from procm. A name can be the id
+// or name of a service, or the id of a remote for its own log and those of all its services.
+func resolveLogSources(procm string, names []string) ([]logSource, error) {
+
+	client, ctx, can, err := rpc.GetControlRequest(procm, time.Second*5)
+	if err != nil {
+		return nil, fmt.Errorf("could not contact gmbhProcm; error=%s", err.Error())
+	}
+	defer can()
+
+	reply, err := client.Summary(ctx, &intrigue.Action{Request: "summary.all"})
+	if err != nil {
+		return nil, fmt.Errorf("could not contact gmbhProcm; error=%s", err.Error())
+	}
+	if reply.GetError() != "" {
+		return nil, fmt.Errorf("could not get summary; error=%s", reply.GetError())
+	}
+
+	sources := []logSource{}
+	seen := make(map[string]bool)
+	add := func(s logSource) {
+		key := s.remoteID + "/" + s.target
+		if !seen[key] {
+			seen[key] = true
+			sources = append(sources, s)
+		}
+	}
+
+	for _, name := range names {
+		found := false
+		for _, remote := range reply.GetRemotes() {
+			whole := name == remote.GetID() || (remote.GetName() != "" && name == remote.GetName())
+			if whole && remote.GetLogPath() != "" {
+				add(logSource{label: remote.GetID(), remoteID: remote.GetID()})
+				found = true
+			}
+			for _, s := range remote.GetServices() {
+				if !whole && name != s.GetId() && name != s.GetName() {
+					continue
+				}
+				if s.GetLogPath() == "" {
+					continue
+				}
+				add(logSource{
+					label:    s.GetName(),
+					remoteID: remote.GetID(),
+					target:   strings.TrimPrefix(s.GetId(), remote.GetID()+"-"),
+				})
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("could not find a log for %s", name)
+		}
+	}
+
+	// services with the same name on different remotes are told apart by their id
+	count := make(map[string]int)
+	for _, s := range sources {
+		count[s.label]++
+	}
+	for i, s := range sources {
+		if count[s.label] > 1 && s.target != "" {
+			sources[i].label = s.remoteID + "-" + s.target
+		}
+	}
+	return sources, nil
+}
+
+// parseSince returns the time of a duration ago or an RFC3339 time
+func parseSince(since string) (time.Time, error) {
+	if d, err := time.ParseDuration(since); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, since)
+}
@@ -26,6 +26,26 @@ var fingerprint = xid.New().String()
 
 func main() {
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "call":
+			callCmd(os.Args[2:])
+			return
+		case "logs":
+			logsCmd(os.Args[2:])
+			return
+		case "top":
+			topCmd(os.Args[2:])
+			return
+		case "context":
+			contextCmd(os.Args[2:])
+			return
+		case "svc":
+			svcCmd(os.Args[2:])
+			return
+		}
+	}
+
 	run := flag.Bool("run", false, "runs a local, managed gmbh server instance. Must specify the config file with the --config flag")
 	deploy := flag.Bool("build-deploy", false, "build a gmbh managed docker cluster")
 	report := flag.Bool("info", false, "combine info with any of the ")
@@ -40,9 +60,19 @@ func main() {
 	restartall := flag.Bool("restart", false, "restart all processes")
 	restartone := flag.String("restart-one", "", "list all processes")
 	q := flag.Bool("q", false, "shutdown gmbh")
+	output := flag.String("output", "", "print the report as json or yaml instead of text")
+
+	context := flag.String("context", "", "the context to use instead of the current context")
+	core := flag.String("core", "", "the address of gmbhCore, overrides the context and config")
+	procm := flag.String("procm", "", "the address of gmbhProcm, overrides the context and config")
 
 	flag.Parse()
 
+	if !validOutput(*output) {
+		print("error, --output must be json or yaml")
+		os.Exit(exitUsage)
+	}
+
 	if *run || *deploy {
 
 		if *config == "" {
@@ -57,17 +87,25 @@ func main() {
 		}
 
 	} else if *report {
+		ep, err := resolveEndpoints(*config, *context, *core, *procm)
+		if err != nil {
+			print("error, %s", err.Error())
+			os.Exit(exitUsage)
+		}
+
+		code := 0
 		if *listall {
-			listAll()
+			code = listAll(ep, *output)
 		} else if *listone != "" {
-			listOne(*listone)
+			code = listOne(ep, *listone, *output)
 		} else if *restartall {
-			restartAll()
+			code = restartAll(ep, *output)
 		} else if *restartone != "" {
-			restartOne(*restartone)
+			code = restartOne(ep, *restartone, *output)
 		} else if *q {
-			shutdown()
+			code = shutdown(ep, *output)
 		}
+		os.Exit(code)
 	}
 }
 
@@ -104,7 +142,7 @@ func start(cfile string, verbose, nolog, daemon bool) {
 		}
 	}
 
-	proccmd, proclog, err := startProcm(nolog)
+	proccmd, proclog, err := startProcm(cfile, nolog)
 	if err != nil {
 		print("could not start ProcM, err=%s", err.Error())
 		os.Exit(1)
@@ -145,9 +183,9 @@ func start(cfile string, verbose, nolog, daemon bool) {
 	}
 }
 
-func startProcm(nolog bool) (*exec.Cmd, *os.File, error) {
+func startProcm(cfile string, nolog bool) (*exec.Cmd, *os.File, error) {
 
-	cmd := exec.Command("gmbhProcm")
+	cmd := exec.Command("gmbhProcm", "--config="+cfile)
 	cmd.Env = append(os.Environ(), "ENV=M")
 
 	var log *os.File
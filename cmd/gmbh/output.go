@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gmbh-micro/rpc/intrigue"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Exit codes of the reporting commands
+const (
+	// exitError is returned when the request was refused or failed
+	exitError = 1
+
+	// exitUsage is returned for invalid flags or arguments
+	exitUsage = 2
+
+	// exitUnreachable is returned when gmbhCore or gmbhProcm could not be contacted
+	exitUnreachable = 3
+
+	// exitServiceFailed is returned by the list commands when any service has failed
+	exitServiceFailed = 4
+)
+
+// Output formats other than the default colored text
+const (
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// validOutput checks the value of --output
+func validOutput(format string) bool {
+	return format == "" || format == "text" || format == outputJSON || format == outputYAML
+}
+
+// writeOutput prints v in format to stdout
+func writeOutput(format string, v interface{}) error {
+	var out []byte
+	var err error
+	switch format {
+	case outputJSON:
+		out, err = json.MarshalIndent(v, "", "  ")
+		out = append(out, '\n')
+	case outputYAML:
+		out, err = yaml.Marshal(v)
+	default:
+		return fmt.Errorf("unknown output format %s", format)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+	return nil
+}
+
+// listOutput is the output of --list and --list-one
+type listOutput struct {
+	Remotes []remoteOutput `json:"remotes" yaml:"remotes"`
+
+	// Unmanaged are the services registered with core that are not run by a remote
+	Unmanaged []serviceOutput `json:"unmanaged,omitempty" yaml:"unmanaged,omitempty"`
+
+	ProcmError string `json:"procmError,omitempty" yaml:"procmError,omitempty"`
+	CoreError  string `json:"coreError,omitempty" yaml:"coreError,omitempty"`
+}
+
+type remoteOutput struct {
+	ID        string          `json:"id" yaml:"id"`
+	Name      string          `json:"name,omitempty" yaml:"name,omitempty"`
+	Address   string          `json:"address" yaml:"address"`
+	Status    string          `json:"status" yaml:"status"`
+	StartTime string          `json:"startTime" yaml:"startTime"`
+	LogPath   string          `json:"logPath,omitempty" yaml:"logPath,omitempty"`
+	Errors    []string        `json:"errors" yaml:"errors"`
+	Services  []serviceOutput `json:"services" yaml:"services"`
+}
+
+// serviceOutput merges what procm and core know about a service
+type serviceOutput struct {
+	ID        string   `json:"id,omitempty" yaml:"id,omitempty"`
+	Name      string   `json:"name" yaml:"name"`
+	Status    string   `json:"status,omitempty" yaml:"status,omitempty"`
+	Mode      string   `json:"mode,omitempty" yaml:"mode,omitempty"`
+	Language  string   `json:"language,omitempty" yaml:"language,omitempty"`
+	PID       int32    `json:"pid,omitempty" yaml:"pid,omitempty"`
+	Restarts  int32    `json:"restarts" yaml:"restarts"`
+	Fails     int32    `json:"fails" yaml:"fails"`
+	StartTime string   `json:"startTime,omitempty" yaml:"startTime,omitempty"`
+	FailTime  string   `json:"failTime,omitempty" yaml:"failTime,omitempty"`
+	LogPath   string   `json:"logPath,omitempty" yaml:"logPath,omitempty"`
+	Errors    []string `json:"errors" yaml:"errors"`
+
+	// Core is set when the service is registered with core
+	Core *coreOutput `json:"core,omitempty" yaml:"core,omitempty"`
+}
+
+type coreOutput struct {
+	Address    string           `json:"address" yaml:"address"`
+	Mode       string           `json:"mode,omitempty" yaml:"mode,omitempty"`
+	PeerGroups []string         `json:"peerGroups" yaml:"peerGroups"`
+	Errors     []string         `json:"errors" yaml:"errors"`
+	Metrics    map[string]int64 `json:"metrics,omitempty" yaml:"metrics,omitempty"`
+	Health     *healthOutput    `json:"health,omitempty" yaml:"health,omitempty"`
+}
+
+// healthOutput is the health that core last saw
+type healthOutput struct {
+	Live    bool              `json:"live" yaml:"live"`
+	Ready   bool              `json:"ready" yaml:"ready"`
+	Message string            `json:"message,omitempty" yaml:"message,omitempty"`
+	Details map[string]string `json:"details,omitempty" yaml:"details,omitempty"`
+	Checked string            `json:"checked,omitempty" yaml:"checked,omitempty"`
+}
+
+// buildListOutput merges the remotes from procm with the services from core the same way
+// as pprintListAll, by the remote id and name of each service
+func buildListOutput(remotes []*intrigue.ProcessManager, services []*intrigue.CoreService) listOutput {
+
+	out := listOutput{Remotes: []remoteOutput{}}
+	attached := make(map[*intrigue.CoreService]bool)
+
+	for _, r := range remotes {
+		ro := remoteOutput{
+			ID:        r.GetID(),
+			Name:      r.GetName(),
+			Address:   r.GetAddress(),
+			Status:    r.GetStatus(),
+			StartTime: r.GetStartTime(),
+			LogPath:   r.GetLogPath(),
+			Errors:    nonNil(r.GetErrors()),
+			Services:  []serviceOutput{},
+		}
+		for _, s := range r.GetServices() {
+			so := serviceOutput{
+				ID:        s.GetId(),
+				Name:      s.GetName(),
+				Status:    s.GetStatus(),
+				Mode:      s.GetMode(),
+				Language:  s.GetLanguage(),
+				PID:       s.GetPid(),
+				Restarts:  s.GetRestarts(),
+				Fails:     s.GetFails(),
+				StartTime: s.GetStartTime(),
+				FailTime:  s.GetFailTime(),
+				LogPath:   s.GetLogPath(),
+				Errors:    nonNil(s.GetErrors()),
+			}
+			for _, c := range services {
+				if c.GetParentID() == r.GetID() && c.GetName() == s.GetName() {
+					so.Core = coreToOutput(c)
+					attached[c] = true
+					break
+				}
+			}
+			ro.Services = append(ro.Services, so)
+		}
+		out.Remotes = append(out.Remotes, ro)
+	}
+
+	for _, c := range services {
+		if !attached[c] {
+			out.Unmanaged = append(out.Unmanaged, serviceOutput{
+				Name:   c.GetName(),
+				Errors: []string{},
+				Core:   coreToOutput(c),
+			})
+		}
+	}
+	return out
+}
+
+func coreToOutput(c *intrigue.CoreService) *coreOutput {
+	out := &coreOutput{
+		Address:    c.GetAddress(),
+		Mode:       c.GetMode(),
+		PeerGroups: nonNil(c.GetPeerGroups()),
+		Errors:     nonNil(c.GetErrors()),
+		Metrics:    c.GetMetrics(),
+	}
+	out.Health = healthToOutput(c.GetHealth())
+	return out
+}
+
+// healthToOutput returns nil if core has no health for the service
+func healthToOutput(h *intrigue.Health) *healthOutput {
+	if h == nil {
+		return nil
+	}
+	return &healthOutput{
+		Live:    h.GetLive(),
+		Ready:   h.GetReady(),
+		Message: h.GetMessage(),
+		Details: h.GetDetails(),
+		Checked: h.GetChecked(),
+	}
+}
+
+// anyFailed returns true if a service run by any of the remotes has failed
+func anyFailed(remotes []*intrigue.ProcessManager) bool {
+	for _, r := range remotes {
+		for _, s := range r.GetServices() {
+			if s.GetStatus() == "Failed" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nonNil returns an empty list for nil so that json shows [] rather than null
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// actionOutput is the output of the commands that ask procm or core to do something
+type actionOutput struct {
+	Request string `json:"request" yaml:"request"`
+	Target  string `json:"target,omitempty" yaml:"target,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// inspectOutput is the output of gmbh svc inspect
+type inspectOutput struct {
+	ID             string        `json:"id" yaml:"id"`
+	Name           string        `json:"name" yaml:"name"`
+	Address        string        `json:"address" yaml:"address"`
+	Aliases        []string      `json:"aliases" yaml:"aliases"`
+	PeerGroups     []string      `json:"peerGroups" yaml:"peerGroups"`
+	State          string        `json:"state" yaml:"state"`
+	Draining       bool          `json:"draining" yaml:"draining"`
+	Added          string        `json:"added" yaml:"added"`
+	FingerprintAge string        `json:"fingerprintAge" yaml:"fingerprintAge"`
+	LastPing       string        `json:"lastPing" yaml:"lastPing"`
+	InFlight       int32         `json:"inFlight" yaml:"inFlight"`
+	Health         *healthOutput `json:"health,omitempty" yaml:"health,omitempty"`
+}
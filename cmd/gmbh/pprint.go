@@ -88,6 +88,10 @@ func pprintListAll(remotes []*intrigue.ProcessManager, services []*intrigue.Core
 		fmt.Println(reportRemotePM(r))
 		for _, s := range m[r.ID] {
 			fmt.Println(reportRemote(n[r.ID][s.Name], s))
+			if h := s.GetHealth(); h != nil && h.GetMessage() != "" && !h.GetReady() {
+				yellow := color.New(color.FgYellow).SprintFunc()
+				fmt.Println(yellow(fmt.Sprintf("   \u2514 %s", h.GetMessage())))
+			}
 		}
 		fmt.Println()
 	}
@@ -149,6 +153,19 @@ func getUptime(t string) string {
 	return ts[:len(ts)-2]
 }
 
+// getHealth returns the health that core last saw, blank if core did not report one
+func getHealth(h *intrigue.Health) string {
+	switch {
+	case h == nil:
+		return fmt.Sprintf("%-9s", "")
+	case !h.GetLive():
+		return color.New(color.FgRed).Sprint(fmt.Sprintf("%-9s", "dead"))
+	case !h.GetReady():
+		return color.New(color.FgYellow).Sprint(fmt.Sprintf("%-9s", "not ready"))
+	}
+	return color.New(color.FgGreen).Sprint(fmt.Sprintf("%-9s", "ready"))
+}
+
 func getName(s string) string {
 	blue := color.New(color.FgBlue).SprintFunc()
 	return blue(fmt.Sprintf("%-12s", s))
@@ -156,9 +173,10 @@ func getName(s string) string {
 
 func reportRemoteHeader() string {
 	u := color.New(color.Underline).SprintFunc()
-	return u(fmt.Sprintf(" %-9s \u2502 %-8s \u2502 %-6s \u2502 %-7s \u2502 %-3s \u2502 %-12s \u2502 %-4s \u2502 %-20s ",
+	return u(fmt.Sprintf(" %-9s \u2502 %-8s \u2502 %-9s \u2502 %-6s \u2502 %-7s \u2502 %-3s \u2502 %-12s \u2502 %-4s \u2502 %-20s ",
 		"ID",
 		"Status",
+		"Health",
 		"PID",
 		"Uptime",
 		"Err",
@@ -169,7 +187,7 @@ func reportRemoteHeader() string {
 }
 
 func reportRemote(p *intrigue.Service, c *intrigue.CoreService) string {
-	var id, status, up, name, address, language string
+	var id, status, health, up, name, address, language string
 	var errs, pid int
 
 	if p != nil {
@@ -181,14 +199,17 @@ func reportRemote(p *intrigue.Service, c *intrigue.CoreService) string {
 		language = p.Language
 	}
 
+	health = getHealth(nil)
 	if c != nil {
 		name = getName(c.Name)
 		address = c.Address
+		health = getHealth(c.GetHealth())
 	}
 
-	return fmt.Sprintf(" %-9s \u2502 %-8s \u2502 %-6d \u2502 %-7s \u2502 %-3d \u2502 %-12s \u2502 %-4s \u2502 %-20s ",
+	return fmt.Sprintf(" %-9s \u2502 %-8s \u2502 %s \u2502 %-6d \u2502 %-7s \u2502 %-3d \u2502 %-12s \u2502 %-4s \u2502 %-20s ",
 		id,
 		status,
+		health,
 		pid,
 		up,
 		errs,
@@ -199,9 +220,10 @@ func reportRemote(p *intrigue.Service, c *intrigue.CoreService) string {
 }
 
 func reportRemotePM(pm *intrigue.ProcessManager) string {
-	return fmt.Sprintf(" %-9s \u2502 %-8s \u2502 %-6s \u2502 %-7s \u2502 %-3d \u2502 %-12s \u2502 %-4s \u2502 %-20s ",
+	return fmt.Sprintf(" %-9s \u2502 %-8s \u2502 %-9s \u2502 %-6s \u2502 %-7s \u2502 %-3d \u2502 %-12s \u2502 %-4s \u2502 %-20s ",
 		pm.ID,
 		getStatus(pm.Status),
+		"",
 		"-",
 		getUptime(pm.StartTime),
 		len(pm.Errors),
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gmbh-micro/notify"
+	"github.com/gmbh-micro/rpc"
+	"github.com/gmbh-micro/rpc/intrigue"
+	"google.golang.org/grpc/metadata"
+)
+
+// svcCmd implements `gmbh svc evict|drain|undrain|inspect <name>`, the admin requests that
+// core answers about one of its services
+func svcCmd(args []string) {
+
+	fs := flag.NewFlagSet("svc", flag.ExitOnError)
+	endpoints := addEndpointFlags(fs)
+	output := fs.String("output", "", "print the result as json or yaml instead of text")
+	adminToken := fs.String("admin-token", "", "the admin_token of the core config, read from the project config if not given")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `usage:
+  gmbh svc evict <name> [flags]     remove the registration of the service from core
+  gmbh svc drain <name> [flags]     stop routing requests to the service
+  gmbh svc undrain <name> [flags]   resume routing requests to the service
+  gmbh svc inspect <name> [flags]   show what core knows of the service
+`)
+		fs.PrintDefaults()
+	}
+
+	pos := parseInterspersed(fs, args)
+	if len(pos) != 2 || !validOutput(*output) {
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+	ep := endpoints.resolve()
+	core := ep.core
+	if *adminToken == "" {
+		*adminToken = ep.adminToken
+	}
+
+	switch pos[0] {
+	case "evict", "drain", "undrain":
+		os.Exit(svcAction(core, *adminToken, pos[0], pos[1], *output))
+	case "inspect":
+		os.Exit(svcInspect(core, pos[1], *output))
+	default:
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+}
+
+// svcAction asks core to evict, drain or undrain the service with name and returns the exit
+// code
+func svcAction(core, adminToken, action, name, output string) int {
+	request := "svc." + action
+
+	// a cluster waits for the change to be committed before it answers
+	client, ctx, can, err := rpc.GetCabalRequest(core, time.Second*10)
+	if err != nil {
+		return actionResult(output, actionOutput{Request: request, Target: name, Error: err.Error()}, exitUnreachable)
+	}
+	defer can()
+	ctx = metadata.AppendToOutgoingContext(ctx, "admin-token", adminToken)
+
+	call := client.EvictService
+	switch action {
+	case "drain":
+		call = client.DrainService
+	case "undrain":
+		call = client.UndrainService
+	}
+	reply, err := call(ctx, &intrigue.Action{Request: request, Target: name})
+	if err != nil {
+		return actionResult(output, actionOutput{Request: request, Target: name, Error: handleErr(err)}, exitUnreachable)
+	}
+	return actionResult(output, actionOutput{
+		Request: request,
+		Target:  name,
+		Message: reply.GetMessage(),
+		Error:   reply.GetError(),
+	}, exitError)
+}
+
+// svcInspect prints what core knows of the service with name and returns the exit code
+func svcInspect(core, name, output string) int {
+	client, ctx, can, err := rpc.GetCabalRequest(core, time.Second*5)
+	if err != nil {
+		notify.LnRedF("could not contact gmbhCore; error=%s", err.Error())
+		return exitUnreachable
+	}
+	defer can()
+
+	reply, err := client.InspectService(ctx, &intrigue.Action{Request: "svc.inspect", Target: name})
+	if err != nil {
+		notify.LnRedF("could not contact gmbhCore; error=%s", handleErr(err))
+		return exitUnreachable
+	}
+	if reply.GetError() != "" {
+		notify.LnRedF("error: %s", reply.GetError())
+		return exitError
+	}
+
+	s := reply.GetService()
+	out := inspectOutput{
+		ID:             s.GetID(),
+		Name:           s.GetName(),
+		Address:        s.GetAddress(),
+		Aliases:        nonNil(s.GetAliases()),
+		PeerGroups:     nonNil(s.GetPeerGroups()),
+		State:          s.GetState(),
+		Draining:       s.GetDraining(),
+		Added:          s.GetAdded(),
+		FingerprintAge: since(s.GetAdded()),
+		LastPing:       s.GetLastPing(),
+		InFlight:       s.GetInFlight(),
+		Health:         healthToOutput(s.GetHealth()),
+	}
+	if output == outputJSON || output == outputYAML {
+		if err := writeOutput(output, out); err != nil {
+			notify.LnRedF("error: " + err.Error())
+			return exitError
+		}
+		return 0
+	}
+
+	row := func(k, v string) { fmt.Printf("%-16s %s\n", k, v) }
+	row("NAME", out.Name)
+	row("ID", out.ID)
+	row("ADDRESS", out.Address)
+	row("ALIASES", strings.Join(out.Aliases, ", "))
+	row("PEER GROUPS", strings.Join(out.PeerGroups, ", "))
+	row("STATE", out.State)
+	row("DRAINING", fmt.Sprint(out.Draining))
+	if h := out.Health; h != nil {
+		row("HEALTH", getHealth(s.GetHealth()))
+		if h.Message != "" {
+			row("", h.Message)
+		}
+	}
+	row("FINGERPRINT AGE", out.FingerprintAge)
+	row("LAST PING", since(out.LastPing)+" ago")
+	row("IN FLIGHT", fmt.Sprint(out.InFlight))
+	return 0
+}
+
+// since returns the time from an RFC3339 time until now to the second
+func since(t string) string {
+	parsed, err := time.Parse(time.RFC3339, t)
+	if err != nil {
+		return "unknown"
+	}
+	return time.Since(parsed).Round(time.Second).String()
+}
@@ -0,0 +1,642 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gmbh-micro/notify"
+	"github.com/gmbh-micro/rpc"
+	"github.com/gmbh-micro/rpc/intrigue"
+	termbox "github.com/nsf/termbox-go"
+)
+
+// topHelp is shown at the bottom of the screen
+const topHelp = "↑/↓ select  r restart  k kill  l logs  / filter  q quit"
+
+// maxRecentErrors is how many of the errors seen are kept for the errors panel
+const maxRecentErrors = 50
+
+// top is the state of gmbh top
+type top struct {
+	core     string
+	procm    string
+	interval time.Duration
+
+	// the last summary
+	remotes  []*intrigue.ProcessManager
+	services map[string]*intrigue.CoreService
+	coreErr  string
+	procmErr string
+	updated  time.Time
+
+	// forwarded is the count of data requests forwarded to each service at the last summary,
+	// rates are requests per second since the one before
+	forwarded map[string]int64
+	rates     map[string]float64
+
+	// recent errors in the order they were first seen
+	recent []string
+	seen   map[string]bool
+
+	rows     []topRow
+	selected string
+
+	filter  string
+	editing bool
+
+	// confirmKill is the row waiting for a y to be killed
+	confirmKill *topRow
+
+	// status is a message shown above the help line
+	status string
+
+	// logs is the view of the log of a service while it is open
+	logs *topLogs
+}
+
+// topRow is a line of the table, either a remote or one of its services
+type topRow struct {
+	remote  *intrigue.ProcessManager
+	service *intrigue.Service
+	core    *intrigue.CoreService
+}
+
+// topLogs follows the log of one service
+type topLogs struct {
+	name   string
+	lines  []string
+	cancel context.CancelFunc
+}
+
+// topSummary is the result of one refresh
+type topSummary struct {
+	remotes  []*intrigue.ProcessManager
+	services []*intrigue.CoreService
+	coreErr  string
+	procmErr string
+	at       time.Time
+}
+
+// topCmd implements `gmbh top`, a live view of the remotes and services of gmbh
+func topCmd(args []string) {
+
+	t := &top{
+		services:  make(map[string]*intrigue.CoreService),
+		forwarded: make(map[string]int64),
+		rates:     make(map[string]float64),
+		seen:      make(map[string]bool),
+	}
+	fs := flag.NewFlagSet("top", flag.ExitOnError)
+	endpoints := addEndpointFlags(fs)
+	fs.DurationVar(&t.interval, "interval", time.Second*2, "how often to refresh")
+	fs.Parse(args)
+
+	ep := endpoints.resolve()
+	t.core, t.procm = ep.core, ep.procm
+
+	if err := termbox.Init(); err != nil {
+		notify.LnRedF("could not start terminal ui; error=%s", err.Error())
+		os.Exit(1)
+	}
+	defer termbox.Close()
+
+	events := make(chan termbox.Event)
+	go func() {
+		for {
+			events <- termbox.PollEvent()
+		}
+	}()
+
+	summaries := make(chan topSummary, 1)
+	refresh := func() {
+		go func() { summaries <- t.fetch() }()
+	}
+	messages := make(chan string, 1)
+	lines := make(chan string, 256)
+
+	refresh()
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		t.draw()
+		select {
+		case <-ticker.C:
+			refresh()
+		case s := <-summaries:
+			t.update(s)
+		case m := <-messages:
+			t.status = m
+			refresh()
+		case l := <-lines:
+			if t.logs != nil {
+				t.logs.lines = append(t.logs.lines, l)
+				if len(t.logs.lines) > 1000 {
+					t.logs.lines = t.logs.lines[len(t.logs.lines)-1000:]
+				}
+			}
+		case ev := <-events:
+			if ev.Type != termbox.EventKey {
+				continue
+			}
+			if quit := t.key(ev, messages, lines); quit {
+				return
+			}
+		}
+	}
+}
+
+// key handles a key press and returns true when top should quit
+func (t *top) key(ev termbox.Event, messages chan string, lines chan string) bool {
+
+	if t.logs != nil {
+		if ev.Key == termbox.KeyEsc || ev.Ch == 'q' {
+			t.logs.cancel()
+			t.logs = nil
+		}
+		return false
+	}
+
+	if t.confirmKill != nil {
+		row := t.confirmKill
+		t.confirmKill = nil
+		if ev.Ch == 'y' {
+			t.status = "killing " + row.service.GetName() + "..."
+			go func() { messages <- t.kill(row) }()
+		} else {
+			t.status = ""
+		}
+		return false
+	}
+
+	if t.editing {
+		switch {
+		case ev.Key == termbox.KeyEnter:
+			t.editing = false
+		case ev.Key == termbox.KeyEsc:
+			t.editing = false
+			t.filter = ""
+		case ev.Key == termbox.KeyBackspace || ev.Key == termbox.KeyBackspace2:
+			if len(t.filter) != 0 {
+				t.filter = t.filter[:len(t.filter)-1]
+			}
+		case ev.Key == termbox.KeySpace:
+			t.filter += " "
+		case ev.Ch != 0:
+			t.filter += string(ev.Ch)
+		}
+		t.buildRows()
+		return false
+	}
+
+	switch {
+	case ev.Key == termbox.KeyCtrlC || ev.Key == termbox.KeyEsc || ev.Ch == 'q':
+		return true
+	case ev.Key == termbox.KeyArrowUp:
+		t.move(-1)
+	case ev.Key == termbox.KeyArrowDown:
+		t.move(1)
+	case ev.Ch == '/':
+		t.editing = true
+	case ev.Ch == 'r':
+		if row := t.selectedRow(); row != nil {
+			t.status = "restarting " + row.service.GetName() + "..."
+			go func() { messages <- t.restart(row) }()
+		}
+	case ev.Ch == 'k':
+		if row := t.selectedRow(); row != nil {
+			t.confirmKill = row
+			t.status = "kill " + row.service.GetName() + "? (y/n)"
+		}
+	case ev.Ch == 'l':
+		if row := t.selectedRow(); row != nil {
+			t.openLogs(row, messages, lines)
+		}
+	}
+	return false
+}
+
+// fetch gets the summaries from procm and core
+func (t *top) fetch() topSummary {
+	s := topSummary{at: time.Now()}
+	{
+		client, ctx, can, err := rpc.GetControlRequest(t.procm, time.Second*2)
+		if err != nil {
+			s.procmErr = err.Error()
+		} else {
+			resp, err := client.Summary(ctx, &intrigue.Action{Request: "summary.all"})
+			can()
+			if err != nil {
+				s.procmErr = handleErr(err)
+			} else {
+				s.procmErr = resp.GetError()
+				s.remotes = resp.GetRemotes()
+			}
+		}
+	}
+	{
+		client, ctx, can, err := rpc.GetCabalRequest(t.core, time.Second*2)
+		if err != nil {
+			s.coreErr = err.Error()
+		} else {
+			resp, err := client.Summary(ctx, &intrigue.Action{Request: "request.info.all"})
+			can()
+			if err != nil {
+				s.coreErr = handleErr(err)
+			} else {
+				s.coreErr = resp.GetError()
+				s.services = resp.GetServices()
+			}
+		}
+	}
+	return s
+}
+
+// update replaces the last summary with s and works out the request rates since then
+func (t *top) update(s topSummary) {
+
+	t.remotes = s.remotes
+	t.coreErr = s.coreErr
+	t.procmErr = s.procmErr
+
+	t.services = make(map[string]*intrigue.CoreService)
+	var metrics map[string]int64
+	for _, cs := range s.services {
+		t.services[cs.GetName()] = cs
+		if cs.GetName() == "CoreData" {
+			metrics = cs.GetMetrics()
+		}
+	}
+
+	elapsed := s.at.Sub(t.updated).Seconds()
+	for k, v := range metrics {
+		if !strings.HasPrefix(k, "data.forwarded.") {
+			continue
+		}
+		name := strings.TrimPrefix(k, "data.forwarded.")
+		if last, ok := t.forwarded[name]; ok && !t.updated.IsZero() && elapsed > 0 {
+			t.rates[name] = float64(v-last) / elapsed
+		}
+		t.forwarded[name] = v
+	}
+	t.updated = s.at
+
+	for _, r := range t.remotes {
+		for _, e := range r.GetErrors() {
+			t.addError(r.GetID() + ": " + e)
+		}
+		for _, svc := range r.GetServices() {
+			for _, e := range svc.GetErrors() {
+				t.addError(svc.GetName() + ": " + e)
+			}
+		}
+	}
+	for _, cs := range s.services {
+		for _, e := range cs.GetErrors() {
+			t.addError(cs.GetName() + ": " + e)
+		}
+	}
+
+	t.buildRows()
+}
+
+func (t *top) addError(e string) {
+	if t.seen[e] {
+		return
+	}
+	t.seen[e] = true
+	t.recent = append(t.recent, e)
+	if len(t.recent) > maxRecentErrors {
+		t.recent = t.recent[len(t.recent)-maxRecentErrors:]
+	}
+}
+
+// buildRows lists the remotes and the services that match the filter
+func (t *top) buildRows() {
+	t.rows = []topRow{}
+	filter := strings.ToLower(t.filter)
+	for _, r := range t.remotes {
+		services := []topRow{}
+		for _, s := range r.GetServices() {
+			row := topRow{remote: r, service: s, core: t.services[s.GetName()]}
+			if filter == "" || row.matches(filter) {
+				services = append(services, row)
+			}
+		}
+		sort.Slice(services, func(i, j int) bool {
+			return services[i].service.GetName() < services[j].service.GetName()
+		})
+		if len(services) == 0 && filter != "" {
+			continue
+		}
+		t.rows = append(t.rows, topRow{remote: r})
+		t.rows = append(t.rows, services...)
+	}
+
+	if t.selectedRow() == nil {
+		t.selected = ""
+		t.move(1)
+	}
+}
+
+// matches checks the name, id and peer groups of the service of row against filter
+func (row topRow) matches(filter string) bool {
+	if strings.Contains(strings.ToLower(row.service.GetName()), filter) ||
+		strings.Contains(strings.ToLower(row.service.GetId()), filter) {
+		return true
+	}
+	for _, g := range row.core.GetPeerGroups() {
+		if strings.Contains(strings.ToLower(g), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectedRow returns the row of the selected service, nil if there is none
+func (t *top) selectedRow() *topRow {
+	for i := range t.rows {
+		if t.rows[i].service != nil && t.rows[i].service.GetId() == t.selected {
+			return &t.rows[i]
+		}
+	}
+	return nil
+}
+
+// move the selection by dir services
+func (t *top) move(dir int) {
+	services := []string{}
+	current := -1
+	for _, row := range t.rows {
+		if row.service == nil {
+			continue
+		}
+		if row.service.GetId() == t.selected {
+			current = len(services)
+		}
+		services = append(services, row.service.GetId())
+	}
+	if len(services) == 0 {
+		t.selected = ""
+		return
+	}
+	next := current + dir
+	if current == -1 || next < 0 {
+		next = 0
+	}
+	if next >= len(services) {
+		next = len(services) - 1
+	}
+	t.selected = services[next]
+}
+
+// target returns the remote id and the id of the service on its remote
+func (row *topRow) target() (string, string) {
+	return row.remote.GetID(), strings.TrimPrefix(row.service.GetId(), row.remote.GetID()+"-")
+}
+
+func (t *top) restart(row *topRow) string {
+	client, ctx, can, err := rpc.GetControlRequest(t.procm, time.Second*20)
+	if err != nil {
+		return "could not contact gmbhProcm; error=" + err.Error()
+	}
+	defer can()
+
+	remoteID, target := row.target()
+	reply, err := client.RestartService(ctx, &intrigue.Action{
+		Request:  "restart.one",
+		RemoteID: remoteID,
+		Target:   target,
+	})
+	if err != nil {
+		return "could not restart " + row.service.GetName() + "; error=" + handleErr(err)
+	}
+	if reply.GetError() != "" {
+		return "could not restart " + row.service.GetName() + "; error=" + reply.GetError()
+	}
+	return "restarted " + row.service.GetName() + "; " + reply.GetMessage()
+}
+
+func (t *top) kill(row *topRow) string {
+	client, ctx, can, err := rpc.GetControlRequest(t.procm, time.Second*5)
+	if err != nil {
+		return "could not contact gmbhProcm; error=" + err.Error()
+	}
+	defer can()
+
+	remoteID, target := row.target()
+	reply, err := client.KillService(ctx, &intrigue.Action{
+		Request:  "kill.one",
+		RemoteID: remoteID,
+		Target:   target,
+	})
+	if err != nil {
+		return "could not kill " + row.service.GetName() + "; error=" + handleErr(err)
+	}
+	if reply.GetError() != "" {
+		return "could not kill " + row.service.GetName() + "; error=" + reply.GetError()
+	}
+	return "killed " + row.service.GetName()
+}
+
+// openLogs follows the log of the service of row until the view is closed
+func (t *top) openLogs(row *topRow, messages chan string, lines chan string) {
+	client, con, err := rpc.DialControl(t.procm)
+	if err != nil {
+		t.status = "could not contact gmbhProcm; error=" + err.Error()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.logs = &topLogs{
+		name: row.service.GetName(),
+		cancel: func() {
+			cancel()
+			con.Close()
+		},
+	}
+
+	remoteID, target := row.target()
+	source := logSource{label: row.service.GetName(), remoteID: remoteID, target: target}
+	request := &intrigue.LogRequest{Follow: true, Tail: 200}
+	go func() {
+		err := streamLog(ctx, client, source, request, func(line string) {
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			messages <- "could not read log of " + source.label + "; error=" + err.Error()
+		}
+	}()
+}
+
+/**********************************************************************************
+**** Drawing
+**********************************************************************************/
+
+func (t *top) draw() {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	width, height := termbox.Size()
+
+	if t.logs != nil {
+		t.drawLogs(width, height)
+		termbox.Flush()
+		return
+	}
+
+	header := fmt.Sprintf(" gmbh top — core %s — procm %s — %s", t.core, t.procm, t.updated.Format("15:04:05"))
+	tbPrint(0, 0, termbox.ColorDefault|termbox.AttrBold, termbox.ColorDefault, header)
+	y := 1
+	if t.procmErr != "" {
+		tbPrint(0, y, termbox.ColorRed, termbox.ColorDefault, " procm: "+t.procmErr)
+		y++
+	}
+	if t.coreErr != "" {
+		tbPrint(0, y, termbox.ColorRed, termbox.ColorDefault, " core: "+t.coreErr)
+		y++
+	}
+	y++
+
+	columns := fmt.Sprintf(" %-12s %-18s %-10s %-9s %7s %8s %6s %8s %8s %6s  %s",
+		"ID", "NAME", "STATUS", "HEALTH", "PID", "RESTARTS", "FAILS", "UPTIME", "REQ/S", "ERRORS", "PEER GROUPS")
+	tbPrint(0, y, termbox.ColorDefault|termbox.AttrReverse, termbox.ColorDefault, pad(columns, width))
+	y++
+
+	// rows, the recent errors and the status and help lines share the screen
+	errorLines := len(t.recent)
+	if errorLines > 5 {
+		errorLines = 5
+	}
+	bottom := height - 3
+	if errorLines != 0 {
+		bottom -= errorLines + 2
+	}
+
+	for _, row := range t.rows {
+		if y >= bottom {
+			break
+		}
+		fg, bg := termbox.ColorDefault, termbox.ColorDefault
+		var line, status, health string
+		if row.service == nil {
+			r := row.remote
+			name := r.GetName()
+			if name == "" {
+				name = "remote"
+			}
+			status = r.GetStatus()
+			line = fmt.Sprintf(" %-12s %-18s %-10s %-9s %7s %8s %6s %8s %8s %6d",
+				r.GetID(), name, status, "", "-", "", "", getUptime(r.GetStartTime()), "", len(r.GetErrors()))
+			fg |= termbox.AttrBold
+		} else {
+			s := row.service
+			status = s.GetStatus()
+			rate := ""
+			if r, ok := t.rates[s.GetName()]; ok {
+				rate = fmt.Sprintf("%.1f", r)
+			}
+			health = topHealth(row.core.GetHealth())
+			line = fmt.Sprintf("   %-10s %-18s %-10s %-9s %7s %8d %6d %8s %8s %6d  %s",
+				s.GetId(), s.GetName(), status, health, getPid(s.GetPid()), s.GetRestarts(), s.GetFails(),
+				getUptime(s.GetStartTime()), rate, len(s.GetErrors())+len(row.core.GetErrors()),
+				strings.Join(row.core.GetPeerGroups(), ","))
+			if s.GetId() == t.selected {
+				bg = termbox.ColorBlue
+			}
+		}
+		tbPrint(0, y, fg, bg, pad(line, width))
+		tbPrint(33, y, statusColor(status)|fg, bg, fmt.Sprintf("%-10s", status))
+		if health != "" {
+			tbPrint(44, y, healthColor(health)|fg, bg, fmt.Sprintf("%-9s", health))
+		}
+		y++
+	}
+
+	if errorLines != 0 {
+		y = height - 3 - errorLines - 1
+		tbPrint(0, y, termbox.ColorDefault|termbox.AttrBold, termbox.ColorDefault, " recent errors")
+		for i, e := range t.recent[len(t.recent)-errorLines:] {
+			tbPrint(0, y+1+i, termbox.ColorRed, termbox.ColorDefault, " "+e)
+		}
+	}
+
+	if t.editing || t.filter != "" {
+		tbPrint(0, height-3, termbox.ColorDefault, termbox.ColorDefault, " filter: "+t.filter)
+		if t.editing {
+			termbox.SetCursor(9+len(t.filter), height-3)
+		}
+	}
+	if !t.editing {
+		termbox.HideCursor()
+	}
+	tbPrint(0, height-2, termbox.ColorYellow, termbox.ColorDefault, " "+t.status)
+	tbPrint(0, height-1, termbox.ColorDefault|termbox.AttrReverse, termbox.ColorDefault, pad(" "+topHelp, width))
+	termbox.Flush()
+}
+
+func (t *top) drawLogs(width, height int) {
+	termbox.HideCursor()
+	tbPrint(0, 0, termbox.ColorDefault|termbox.AttrReverse, termbox.ColorDefault, pad(" logs of "+t.logs.name+" — esc to close", width))
+	lines := t.logs.lines
+	if len(lines) > height-1 {
+		lines = lines[len(lines)-(height-1):]
+	}
+	for i, l := range lines {
+		tbPrint(0, i+1, termbox.ColorDefault, termbox.ColorDefault, l)
+	}
+}
+
+func statusColor(s string) termbox.Attribute {
+	switch s {
+	case "Stable", "Running":
+		return termbox.ColorGreen
+	case "Degraded", "Restarting", "Initialized":
+		return termbox.ColorYellow
+	}
+	return termbox.ColorRed
+}
+
+// topHealth is the health that core last saw, blank if it did not report one
+func topHealth(h *intrigue.Health) string {
+	switch {
+	case h == nil:
+		return ""
+	case !h.GetLive():
+		return "dead"
+	case !h.GetReady():
+		return "not ready"
+	}
+	return "ready"
+}
+
+func healthColor(s string) termbox.Attribute {
+	switch s {
+	case "ready":
+		return termbox.ColorGreen
+	case "not ready":
+		return termbox.ColorYellow
+	}
+	return termbox.ColorRed
+}
+
+// tbPrint writes s at x, y
+func tbPrint(x, y int, fg, bg termbox.Attribute, s string) {
+	for _, c := range s {
+		termbox.SetCell(x, y, c, fg, bg)
+		x++
+	}
+}
+
+// pad s with spaces to width
+func pad(s string, width int) string {
+	if n := width - len([]rune(s)); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/gmbh-micro/config"
+	"github.com/gmbh-micro/fileutil"
+)
+
+// AccessList enforces the access control rules from the project config. When there are no
+// rules, every call between services is allowed and only the peer groups are considered.
+type AccessList struct {
+	rules []*config.ACLRule
+
+	// auditPath is where denials are recorded, the file is opened on the first denial
+	auditPath string
+	audit     *os.File
+
+	metrics *Metrics
+	mu      *sync.Mutex
+}
+
+// NewAccessList returns an access list enforcing rules
+func NewAccessList(rules []*config.ACLRule, auditPath string, metrics *Metrics) *AccessList {
+	return &AccessList{
+		rules:     rules,
+		auditPath: auditPath,
+		metrics:   metrics,
+		mu:        &sync.Mutex{},
+	}
+}
+
+// Enabled returns true if there are rules to enforce
+func (a *AccessList) Enabled() bool {
+	return a != nil && len(a.rules) != 0
+}
+
+// Allowed returns true if from may call method on to
+func (a *AccessList) Allowed(from string, to *GmbhService, method string) bool {
+	if !a.Enabled() {
+		return true
+	}
+	for _, rule := range a.rules {
+		if !a.matchesServices(rule, from, to) {
+			continue
+		}
+		for _, m := range rule.Methods {
+			if ok, _ := path.Match(m, method); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AllowedAll returns true if from may call any method on to. This is required before core
+// will grant a direct connection as those calls can no longer be checked by core.
+func (a *AccessList) AllowedAll(from string, to *GmbhService) bool {
+	if !a.Enabled() {
+		return true
+	}
+	for _, rule := range a.rules {
+		if !a.matchesServices(rule, from, to) {
+			continue
+		}
+		for _, m := range rule.Methods {
+			if m == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Deny records that from was refused access to method on to. The source is either
+// "data" for forwarded requests or "websocket" for those from browsers.
+func (a *AccessList) Deny(from, to, method, source string) {
+	a.metrics.Inc("acl.denied")
+	a.metrics.Inc("acl.denied." + to)
+
+	entry := fmt.Sprintf("denied; source=%s; from=%s; to=%s; method=%s", source, from, to, method)
+	print("acl %s", entry)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.audit == nil {
+		if a.auditPath == "" {
+			return
+		}
+		f, err := fileutil.OpenFile(a.auditPath)
+		if err != nil {
+			print("could not open audit log; err=%s", err.Error())
+			return
+		}
+		a.audit = f
+	}
+	a.audit.WriteString("[" + time.Now().Format(config.LogStamp) + "] " + entry + "\n")
+}
+
+// Close the audit file if it has been opened
+func (a *AccessList) Close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.audit != nil {
+		a.audit.Close()
+		a.audit = nil
+	}
+}
+
+// matchesServices checks the from and to patterns of the rule. The to pattern may match
+// either the name of the service or any of its aliases.
+func (a *AccessList) matchesServices(rule *config.ACLRule, from string, to *GmbhService) bool {
+	if ok, _ := path.Match(rule.From, from); !ok {
+		return false
+	}
+	if ok, _ := path.Match(rule.To, to.Name); ok {
+		return true
+	}
+	for _, alias := range to.aliases() {
+		if ok, _ := path.Match(rule.To, alias); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gmbh-micro/rpc"
+	"github.com/gmbh-micro/rpc/intrigue"
+	"google.golang.org/grpc/metadata"
+)
+
+/*
+	Admin requests name the service in the Target of the action. Evicting, draining and
+	undraining change the registration, need the admin token of the core config and are
+	made by the leader of a cluster, inspecting is answered by the core that is asked.
+*/
+
+func (s *cabalServer) EvictService(ctx context.Context, in *intrigue.Action) (*intrigue.Receipt, error) {
+
+	print("-> Evict; Action=%s", in.String())
+
+	c, err := GetCore()
+	if err != nil {
+		return &intrigue.Receipt{Error: "core.ref"}, nil
+	}
+
+	if !c.isAdmin(ctx) {
+		print("refused %s of %s; admin token not sent or wrong", in.GetRequest(), in.GetTarget())
+		return &intrigue.Receipt{Error: "permission.denied"}, nil
+	}
+
+	if c.Router.cluster != nil && !c.Router.cluster.IsLeader() {
+		return c.Router.cluster.forwardAdmin(ctx, in, intrigue.CabalClient.EvictService), nil
+	}
+
+	service, err := c.Router.LookupService(in.GetTarget())
+	if err != nil {
+		return &intrigue.Receipt{Error: "service.notFound"}, nil
+	}
+	if err := c.Router.evict(service); err != nil {
+		return &intrigue.Receipt{Error: "registry.unavailable"}, nil
+	}
+	return &intrigue.Receipt{Message: "evicted " + service.Name}, nil
+}
+
+func (s *cabalServer) DrainService(ctx context.Context, in *intrigue.Action) (*intrigue.Receipt, error) {
+	print("-> Drain; Action=%s", in.String())
+	return setDraining(ctx, in, true, intrigue.CabalClient.DrainService), nil
+}
+
+func (s *cabalServer) UndrainService(ctx context.Context, in *intrigue.Action) (*intrigue.Receipt, error) {
+	print("-> Undrain; Action=%s", in.String())
+	return setDraining(ctx, in, false, intrigue.CabalClient.UndrainService), nil
+}
+
+// setDraining drains or undrains the target of the action, forwarding it with call when
+// this core is not the leader
+func setDraining(ctx context.Context, in *intrigue.Action, draining bool, call adminCall) *intrigue.Receipt {
+	c, err := GetCore()
+	if err != nil {
+		return &intrigue.Receipt{Error: "core.ref"}
+	}
+
+	if !c.isAdmin(ctx) {
+		print("refused %s of %s; admin token not sent or wrong", in.GetRequest(), in.GetTarget())
+		return &intrigue.Receipt{Error: "permission.denied"}
+	}
+
+	if c.Router.cluster != nil && !c.Router.cluster.IsLeader() {
+		return c.Router.cluster.forwardAdmin(ctx, in, call)
+	}
+
+	service, err := c.Router.LookupService(in.GetTarget())
+	if err != nil {
+		return &intrigue.Receipt{Error: "service.notFound"}
+	}
+	if err := c.Router.drain(service, draining); err != nil {
+		return &intrigue.Receipt{Error: "registry.unavailable"}
+	}
+	if draining {
+		return &intrigue.Receipt{Message: "draining " + service.Name}
+	}
+	return &intrigue.Receipt{Message: "undrained " + service.Name}
+}
+
+func (s *cabalServer) InspectService(ctx context.Context, in *intrigue.Action) (*intrigue.InspectReceipt, error) {
+
+	print("-> Inspect; Action=%s", in.String())
+
+	c, err := GetCore()
+	if err != nil {
+		return &intrigue.InspectReceipt{Error: "core.ref"}, nil
+	}
+
+	service, err := c.Router.LookupService(in.GetTarget())
+	if err != nil {
+		return &intrigue.InspectReceipt{Error: "service.notFound"}, nil
+	}
+
+	detail := service.detail()
+	detail.InFlight = int32(c.limiter.InFlight(service.Name))
+	detail.Health = service.healthProto()
+	return &intrigue.InspectReceipt{Service: detail}, nil
+}
+
+// isAdmin returns true if the request carries the admin token of the core config
+func (c *Core) isAdmin(ctx context.Context) bool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	token := strings.Join(md.Get("admin-token"), "")
+	return c.conf.AdminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(c.conf.AdminToken)) == 1
+}
+
+// drain stops or resumes routing to s. The change is persisted first and then the service
+// is told, so that peers that call it directly are turned away as well. The service is told
+// even if core had it that way already, in case it missed an earlier notice.
+func (r *Router) drain(s *GmbhService, draining bool) error {
+	s.mu.Lock()
+	changed := s.Draining != draining
+	s.Draining = draining
+	s.mu.Unlock()
+
+	event := "undrained"
+	request := "core.undrain"
+	if draining {
+		event = "drained"
+		request = "core.drain"
+	}
+
+	if changed {
+		if err := r.persist(s); err != nil {
+			s.mu.Lock()
+			s.Draining = !draining
+			s.mu.Unlock()
+			return err
+		}
+		print("%s service=%s", event, s.String())
+		if core != nil {
+			core.events.Publish(topicServices, serviceEvent(event, s, s.State))
+		}
+	}
+
+	s.mu.Lock()
+	address, fingerprint := s.Address, s.Fingerprint
+	s.mu.Unlock()
+
+	client, ctx, can, err := rpc.GetCabalRequest(address, time.Second)
+	if err != nil {
+		return nil
+	}
+	defer can()
+
+	// the service only takes the notice from core, which signs it with its fingerprint
+	ctx = metadata.AppendToOutgoingContext(ctx, "sender", "core", "token", rpc.SignSender(fingerprint, request))
+	if _, err := client.UpdateRegistration(ctx, &intrigue.ServiceUpdate{Request: request, Message: s.Name}); err != nil {
+		print("could not tell %s(%s) of %s; err=%s", s.Name, s.ID, request, err.Error())
+	}
+	return nil
+}
+
+// isDraining returns true if requests are not to be routed to the service
+func (g *GmbhService) isDraining() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.Draining
+}
+
+// detail returns what the router knows of the service for an inspection
+func (g *GmbhService) detail() *intrigue.ServiceDetail {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	pg := make([]string, 0, len(g.PeerGroups))
+	for k := range g.PeerGroups {
+		pg = append(pg, k)
+	}
+	sort.Strings(pg)
+	return &intrigue.ServiceDetail{
+		ID:         g.ID,
+		Name:       g.Name,
+		Address:    g.Address,
+		Aliases:    g.Aliases,
+		PeerGroups: pg,
+		State:      g.State.String(),
+		Added:      g.Added.Format(time.RFC3339),
+		LastPing:   g.LastPing.Format(time.RFC3339),
+		Draining:   g.Draining,
+	}
+}
@@ -2,11 +2,13 @@ package main
 
 import (
 	"context"
+	"io"
 	"strings"
 	"time"
 
 	"github.com/gmbh-micro/rpc"
 	"github.com/gmbh-micro/rpc/intrigue"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -24,19 +26,39 @@ func (s *cabalServer) RegisterService(ctx context.Context, in *intrigue.NewServi
 		return &intrigue.Receipt{Error: "error.coreref"}, nil
 	}
 
+	// only the leader of a cluster assigns ids and addresses
+	if c.Router.cluster != nil && !c.Router.cluster.IsLeader() {
+		return c.Router.cluster.forwardRegistration(in), nil
+	}
+
 	newService := in.GetService()
 
-	ns, err := c.Router.AddService(newService.GetName(), newService.GetAliases(), newService.GetPeerGroups(), in.GetEnv(), in.GetAddress())
+	compression := rpc.NegotiateCompression(newService.GetCompression(), c.conf.Compression)
+	lim := serviceLimit{Rate: newService.GetRateLimit(), Burst: int(newService.GetBurst()), MaxInFlight: int(newService.GetMaxInFlight())}
+	ns, err := c.Router.AddService(newService.GetName(), newService.GetAliases(), newService.GetPeerGroups(), in.GetEnv(), in.GetAddress(), int(newService.GetPort()), compression, lim)
 	if err != nil {
 		return &intrigue.Receipt{Error: err.Error()}, nil
 	}
+	applyLimit(ns)
+
+	c.events.Publish(topicServices, serviceEvent("registered", ns, Running))
+
+	// services in containers listen on the address they gave
+	bindHost := c.conf.BindHost
+	if in.GetEnv() == "C" {
+		bindHost = ""
+	}
 
 	return &intrigue.Receipt{
 		Message: "acknowledged",
 		ServiceInfo: &intrigue.ServiceSummary{
-			Address:     ns.Address,
-			ID:          ns.ID,
-			Fingerprint: ns.Fingerprint,
+			Address:              ns.Address,
+			ID:                   ns.ID,
+			Fingerprint:          ns.Fingerprint,
+			Compression:          compression,
+			CompressionThreshold: int32(c.conf.CompressionThreshold),
+			BindHost:             bindHost,
+			Draining:             ns.isDraining(),
 		},
 	}, nil
 
@@ -54,6 +76,10 @@ func (s *cabalServer) UpdateRegistration(ctx context.Context, in *intrigue.Servi
 		return &intrigue.Receipt{Error: "error.coreref"}, nil
 	}
 
+	if c.Router.cluster != nil && !c.Router.cluster.IsLeader() {
+		return c.Router.cluster.forwardUpdate(ctx, in), nil
+	}
+
 	if request == "shutdown.notif" {
 		service, err := c.Router.LookupService(name)
 		if err != nil {
@@ -62,7 +88,18 @@ func (s *cabalServer) UpdateRegistration(ctx context.Context, in *intrigue.Servi
 			}, nil
 		}
 
-		service.UpdateState(Shutdown)
+		// only the service itself may give up its registration and address
+		md, _ := metadata.FromIncomingContext(ctx)
+		fp := strings.Join(md.Get("fingerprint"), "")
+		if err := c.Router.Verify(service.Name, fp); err != nil {
+			print("could not verify shutdown of %s; err=%s", name, err.Error())
+			return &intrigue.Receipt{Error: err.Error()}, nil
+		}
+
+		if err := service.UpdateState(Shutdown); err != nil {
+			return &intrigue.Receipt{Error: "registry.unavailable"}, nil
+		}
+		c.Router.addressing.Release(service.Address)
 		return &intrigue.Receipt{Message: "ack"}, nil
 	}
 
@@ -77,11 +114,30 @@ func (s *cabalServer) Data(ctx context.Context, in *intrigue.DataRequest) (*intr
 	tport := in.GetRequest().GetTport()
 	print("-%d-> Data request: %s", cnt, tport.String())
 
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || tport == nil {
+		print("<-%d- invalid request", cnt)
+		return &intrigue.DataResponse{Error: "invalid request"}, nil
+	}
+
 	c, err := GetCore()
 	if err != nil {
 		print("<-%d- could not get core error=%s", cnt, err.Error())
 		return &intrigue.DataResponse{Error: "core.ref"}, nil
 	}
+	c.metrics.Inc("data.requests")
+
+	sender := strings.Join(md.Get("sender"), "")
+	fp := strings.Join(md.Get("fingerprint"), "")
+
+	verified := c.Router.Verify(sender, fp)
+	if verified != nil {
+		print("<-%d- could not verify %s; err=%s", cnt, sender, verified.Error())
+		return &intrigue.DataResponse{Error: "sender.unverified"}, nil
+	}
+
+	// handlers only ever see the sender that core was able to verify
+	tport.Sender = sender
 
 	fwd, err := c.Router.LookupService(tport.GetTarget())
 	if err != nil {
@@ -89,26 +145,192 @@ func (s *cabalServer) Data(ctx context.Context, in *intrigue.DataRequest) (*intr
 		return &intrigue.DataResponse{Error: "service.notFound"}, nil
 	}
 
-	client, ctx, can, err := rpc.GetCabalRequest(fwd.Address, time.Second*2)
+	err = c.Router.GrantMethod(sender, fwd.Name, tport.GetMethod())
+	if err != nil {
+		print("<-%d- permission denied; %s -> %s.%s", cnt, sender, fwd.Name, tport.GetMethod())
+		return &intrigue.DataResponse{Error: "permission.denied"}, nil
+	}
+
+	final := c.forward(sender, fwd, in)
+	print("<-%d- elapsed time=%s", cnt, time.Since(t))
+	return final, nil
+}
+
+// forward sends the data request from sender on to fwd once the limiter has a slot for it
+func (c *Core) forward(sender string, fwd *GmbhService, in *intrigue.DataRequest) *intrigue.DataResponse {
+	if fwd.isDraining() {
+		print("<-%d- service draining; %s -> %s", cnt, sender, fwd.Name)
+		c.metrics.Inc("data.draining")
+		return &intrigue.DataResponse{Error: "service.draining"}
+	}
+	if !fwd.Ready() {
+		print("<-%d- service not ready; %s -> %s", cnt, sender, fwd.Name)
+		c.metrics.Inc("data.notReady")
+		return &intrigue.DataResponse{Error: "service.notReady"}
+	}
+
+	release, err := c.limiter.Acquire(sender, fwd.Name)
+	if err != nil {
+		print("<-%d- %s; %s -> %s", cnt, err.Error(), sender, fwd.Name)
+		return &intrigue.DataResponse{Error: err.Error()}
+	}
+	defer release()
+
+	client, ctx, can, err := rpc.GetCabalRequest(fwd.Address, time.Second*2, grpc.WithStatsHandler(c.compression))
 	if err != nil {
 		print("<-%d- rpc error=%s", cnt, err.Error())
-		return &intrigue.DataResponse{Error: "rpc error=" + err.Error()}, nil
+		return &intrigue.DataResponse{Error: "rpc error=" + err.Error()}
 	}
 	defer can()
-	final, err := client.Data(ctx, in)
+	ctx = metadata.AppendToOutgoingContext(
+		ctx,
+		"sender", sender,
+		"token", rpc.SignSender(fwd.Fingerprint, sender),
+	)
+	fwd.mu.Lock()
+	compression := fwd.Compression
+	fwd.mu.Unlock()
+	final, err := client.Data(ctx, in, rpc.CompressionOption(compression, c.conf.CompressionThreshold, in)...)
 	if err != nil {
 		print("<-%d- could not forward error=%s", cnt, err.Error())
-		return &intrigue.DataResponse{Error: "unableToForward"}, nil
+		return &intrigue.DataResponse{Error: "unableToForward"}
 	}
-	print("<-%d- elapsed time=%s", cnt, time.Since(t))
-	return final, nil
+	c.metrics.Inc("data.forwarded")
+	c.metrics.Inc("data.forwarded." + fwd.Name)
+	return final
+}
+
+func (s *cabalServer) DataStream(stream intrigue.Cabal_DataStreamServer) error {
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	tport := first.GetTport()
+	print("-> Data stream: %s", tport.String())
+
+	md, ok := metadata.FromIncomingContext(stream.Context())
+	if !ok || tport == nil {
+		print("<- invalid stream")
+		return stream.Send(&intrigue.StreamChunk{Error: "invalid request"})
+	}
+
+	c, err := GetCore()
+	if err != nil {
+		print("<- could not get core error=%s", err.Error())
+		return stream.Send(&intrigue.StreamChunk{Error: "core.ref"})
+	}
+	c.metrics.Inc("stream.requests")
+
+	sender := strings.Join(md.Get("sender"), "")
+	fp := strings.Join(md.Get("fingerprint"), "")
+
+	verified := c.Router.Verify(sender, fp)
+	if verified != nil {
+		print("<- could not verify %s; err=%s", sender, verified.Error())
+		return stream.Send(&intrigue.StreamChunk{Error: "sender.unverified"})
+	}
+	tport.Sender = sender
+
+	fwd, err := c.Router.LookupService(tport.GetTarget())
+	if err != nil {
+		print("<- service not found error=%s", err.Error())
+		return stream.Send(&intrigue.StreamChunk{Error: "service.notFound"})
+	}
+
+	err = c.Router.GrantMethod(sender, fwd.Name, tport.GetMethod())
+	if err != nil {
+		print("<- permission denied; %s -> %s.%s", sender, fwd.Name, tport.GetMethod())
+		return stream.Send(&intrigue.StreamChunk{Error: "permission.denied"})
+	}
+
+	if fwd.isDraining() {
+		print("<- service draining; %s -> %s", sender, fwd.Name)
+		c.metrics.Inc("data.draining")
+		return stream.Send(&intrigue.StreamChunk{Error: "service.draining"})
+	}
+	if !fwd.Ready() {
+		print("<- service not ready; %s -> %s", sender, fwd.Name)
+		c.metrics.Inc("data.notReady")
+		return stream.Send(&intrigue.StreamChunk{Error: "service.notReady"})
+	}
+
+	// a stream holds its slot until it is done
+	release, err := c.limiter.Acquire(sender, fwd.Name)
+	if err != nil {
+		print("<- %s; %s -> %s", err.Error(), sender, fwd.Name)
+		return stream.Send(&intrigue.StreamChunk{Error: err.Error()})
+	}
+	defer release()
+
+	client, con, err := rpc.DialCabal(fwd.Address)
+	if err != nil {
+		print("<- rpc error=%s", err.Error())
+		return stream.Send(&intrigue.StreamChunk{Error: "rpc error=" + err.Error()})
+	}
+	defer con.Close()
+
+	ctx, can := context.WithCancel(stream.Context())
+	defer can()
+	ctx = metadata.AppendToOutgoingContext(
+		ctx,
+		"sender", sender,
+		"token", rpc.SignSender(fwd.Fingerprint, sender),
+	)
+	out, err := client.DataStream(ctx)
+	if err == nil {
+		err = out.Send(first)
+	}
+	if err != nil {
+		print("<- could not forward error=%s", err.Error())
+		return stream.Send(&intrigue.StreamChunk{Error: "unableToForward"})
+	}
+	c.metrics.Inc("stream.forwarded")
+	c.metrics.Inc("stream.forwarded." + fwd.Name)
+
+	// caller -> target; the target stream is half closed once the caller is done sending
+	go func() {
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				out.CloseSend()
+				return
+			}
+			if err != nil {
+				can()
+				return
+			}
+			c.metrics.Add("stream.bytes", int64(len(chunk.GetData())))
+			if out.Send(chunk) != nil {
+				return
+			}
+		}
+	}()
+
+	// target -> caller until the target handler returns
+	for {
+		chunk, err := out.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			print("<- stream to %s failed; err=%s", fwd.Name, err.Error())
+			return stream.Send(&intrigue.StreamChunk{Error: "unableToForward"})
+		}
+		c.metrics.Add("stream.bytes", int64(len(chunk.GetData())))
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+	}
+	print("<- stream closed; %s -> %s.%s", sender, fwd.Name, tport.GetMethod())
+	return nil
 }
 
 func (s *cabalServer) WhoIs(ctx context.Context, in *intrigue.WhoIsRequest) (*intrigue.WhoIsResponse, error) {
 
 	print("-> WhoIsRequest=%s", in.String())
 	target := in.GetTarget()
-	sender := in.GetSender()
 
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -129,7 +351,14 @@ func (s *cabalServer) WhoIs(ctx context.Context, in *intrigue.WhoIsRequest) (*in
 		return &intrigue.WhoIsResponse{Error: verified.Error()}, nil
 	}
 
+	// the sender in the request body is ignored in favor of the verified one
+	sender := name
+
 	addr, err := c.Router.GrantPermissions(sender, target)
+	if err == errViaCore {
+		print("<- through core; %s -> %s", sender, target)
+		return &intrigue.WhoIsResponse{Error: "whois.viaCore"}, nil
+	}
 	if err != nil {
 		if err.Error() == "denied" {
 			print("<- mismatch peer groups; %s -> %s", sender, target)
@@ -139,8 +368,30 @@ func (s *cabalServer) WhoIs(ctx context.Context, in *intrigue.WhoIsRequest) (*in
 		return &intrigue.WhoIsResponse{Error: "server.error"}, nil
 	}
 
+	serv, err := c.Router.LookupService(target)
+	if err != nil {
+		return &intrigue.WhoIsResponse{Error: "server.error"}, nil
+	}
+	if serv.isDraining() {
+		print("<- draining; %s -> %s", sender, target)
+		return &intrigue.WhoIsResponse{Error: "service.draining"}, nil
+	}
+	if !serv.Ready() {
+		print("<- not ready; %s -> %s", sender, target)
+		return &intrigue.WhoIsResponse{Error: "service.notReady"}, nil
+	}
+
+	// requests sent directly would not be counted against the limits
+	if c.limiter.Limited(sender, serv.Name) {
+		print("<- limited, through core; %s -> %s", sender, target)
+		return &intrigue.WhoIsResponse{Error: "whois.viaCore"}, nil
+	}
+
 	print("<- granted; %s -> %s", sender, target)
-	return &intrigue.WhoIsResponse{TargetAddress: addr}, nil
+	return &intrigue.WhoIsResponse{
+		TargetAddress: addr,
+		Token:         rpc.SignSender(serv.Fingerprint, sender),
+	}, nil
 
 }
 
@@ -154,12 +405,21 @@ func (s *cabalServer) Summary(ctx context.Context, in *intrigue.Action) (*intrig
 		return &intrigue.SummaryReceipt{Error: "core.ref"}, nil
 	}
 
+	metrics := c.metrics.Snapshot()
+	for k, v := range c.compression.Snapshot() {
+		metrics[k] = v
+	}
+
 	// add core itself
 	ccs := &intrigue.CoreService{
 		Name:       "CoreData",
 		PeerGroups: []string{"core"},
 		Address:    c.conf.Address,
 		ParentID:   c.parentID,
+		Metrics:    metrics,
+	}
+	if c.Router.cluster != nil {
+		ccs.Mode = c.Router.cluster.Role()
 	}
 
 	request := in.GetRequest()
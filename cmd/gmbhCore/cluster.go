@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gmbh-micro/config"
+	"github.com/gmbh-micro/rpc"
+	"github.com/gmbh-micro/rpc/intrigue"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// clusterApplyTimeout is how long the leader waits for a change to be committed by a majority
+// of the cluster before reporting it as failed
+const clusterApplyTimeout = time.Second * 5
+
+// Cluster replicates the registry between the cores of a highly available setup with raft.
+// Every core serves services from its own copy of the registry, while changes to it are made
+// by the leader and only acknowledged once a majority of the cores have them.
+type Cluster struct {
+	self  *config.ClusterPeer
+	peers []*config.ClusterPeer
+
+	raft   *raft.Raft
+	store  *raftboltdb.BoltStore
+	router *Router
+}
+
+// NewCluster starts the raft node of the core at address. The raft log and snapshots are kept
+// in dir. The first time a cluster is started every core bootstraps it with the same peers, from
+// then on the membership is read back from the log.
+func NewCluster(address string, peers []*config.ClusterPeer, dir string, router *Router) (*Cluster, error) {
+
+	c := &Cluster{
+		peers:  peers,
+		router: router,
+	}
+	for _, p := range peers {
+		if p.Address == address {
+			c.self = p
+		}
+	}
+	if c.self == nil {
+		return nil, errors.New("cluster: " + address + " is not one of the cores in the cluster")
+	}
+
+	// the addresses of the cores must not be handed out to services
+	for _, p := range peers {
+		router.addressing.Reserve(p.Address)
+		router.addressing.Reserve(p.Raft)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	logs := &raftLog{}
+
+	conf := raft.DefaultConfig()
+	conf.LocalID = raft.ServerID(c.self.Address)
+	conf.LogOutput = logs
+	conf.LogLevel = "WARN"
+
+	var err error
+	c.store, err = raftboltdb.NewBoltStore(filepath.Join(dir, "raft.db"))
+	if err != nil {
+		return nil, err
+	}
+	snaps, err := raft.NewFileSnapshotStore(dir, 2, logs)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := raft.NewTCPTransport(c.self.Raft, nil, 3, time.Second*10, logs)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := raft.HasExistingState(c.store, c.store, snaps)
+	if err != nil {
+		return nil, err
+	}
+	if !existing {
+		servers := raft.Configuration{}
+		for _, p := range peers {
+			servers.Servers = append(servers.Servers, raft.Server{
+				ID:      raft.ServerID(p.Address),
+				Address: raft.ServerAddress(p.Raft),
+			})
+		}
+		err = raft.BootstrapCluster(conf, c.store, c.store, snaps, transport, servers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.raft, err = raft.NewRaft(conf, &clusterFSM{router: router}, c.store, c.store, snaps, transport)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Put replicates the record to the cluster, it may only be called on the leader
+func (c *Cluster) Put(rec *registryRecord) error {
+	data, err := json.Marshal(&walEntry{Op: "put", Service: rec})
+	if err != nil {
+		return err
+	}
+	return c.raft.Apply(data, clusterApplyTimeout).Error()
+}
+
+// Remove replicates the removal of the service in rec, it may only be called on the leader
+func (c *Cluster) Remove(rec *registryRecord) error {
+	data, err := json.Marshal(&walEntry{Op: "remove", Service: rec})
+	if err != nil {
+		return err
+	}
+	return c.raft.Apply(data, clusterApplyTimeout).Error()
+}
+
+// IsLeader returns true if this core is the leader of the cluster
+func (c *Cluster) IsLeader() bool {
+	return c.raft.State() == raft.Leader
+}
+
+// Leader returns the address that the leader serves services on, empty if there is no leader
+func (c *Cluster) Leader() string {
+	_, id := c.raft.LeaderWithID()
+	return string(id)
+}
+
+// Role is the raft state of this core, such as leader or follower
+func (c *Cluster) Role() string {
+	return strings.ToLower(c.raft.State().String())
+}
+
+// forwardRegistration sends a registration made to a follower on to the leader
+func (c *Cluster) forwardRegistration(in *intrigue.NewServiceRequest) *intrigue.Receipt {
+	leader := c.Leader()
+	if leader == "" {
+		return &intrigue.Receipt{Error: "cluster.noLeader"}
+	}
+	client, ctx, can, err := rpc.GetCabalRequest(leader, clusterApplyTimeout)
+	if err != nil {
+		return &intrigue.Receipt{Error: "cluster.leaderUnavailable"}
+	}
+	defer can()
+	receipt, err := client.RegisterService(ctx, in)
+	if err != nil {
+		print("could not forward registration to %s; err=%s", leader, err.Error())
+		return &intrigue.Receipt{Error: "cluster.leaderUnavailable"}
+	}
+	if receipt.GetError() == "" {
+		c.awaitService(in.GetService().GetName(), receipt.GetServiceInfo().GetFingerprint())
+	}
+	return receipt
+}
+
+// forwardUpdate sends a registration update made to a follower on to the leader along with
+// the metadata that identifies the sender
+func (c *Cluster) forwardUpdate(ctx context.Context, in *intrigue.ServiceUpdate) *intrigue.Receipt {
+	leader := c.Leader()
+	if leader == "" {
+		return &intrigue.Receipt{Error: "cluster.noLeader"}
+	}
+	client, fwdCtx, can, err := rpc.GetCabalRequest(leader, clusterApplyTimeout)
+	if err != nil {
+		return &intrigue.Receipt{Error: "cluster.leaderUnavailable"}
+	}
+	defer can()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		fwdCtx = metadata.NewOutgoingContext(fwdCtx, md)
+	}
+	receipt, err := client.UpdateRegistration(fwdCtx, in)
+	if err != nil {
+		print("could not forward update to %s; err=%s", leader, err.Error())
+		return &intrigue.Receipt{Error: "cluster.leaderUnavailable"}
+	}
+	return receipt
+}
+
+// adminCall is one of the admin requests of the cabal client, such as CabalClient.EvictService
+type adminCall func(intrigue.CabalClient, context.Context, *intrigue.Action, ...grpc.CallOption) (*intrigue.Receipt, error)
+
+// forwardAdmin sends an admin request made to a follower on to the leader along with the
+// metadata that carries the admin token
+func (c *Cluster) forwardAdmin(ctx context.Context, in *intrigue.Action, call adminCall) *intrigue.Receipt {
+	leader := c.Leader()
+	if leader == "" {
+		return &intrigue.Receipt{Error: "cluster.noLeader"}
+	}
+	client, fwdCtx, can, err := rpc.GetCabalRequest(leader, clusterApplyTimeout)
+	if err != nil {
+		return &intrigue.Receipt{Error: "cluster.leaderUnavailable"}
+	}
+	defer can()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		fwdCtx = metadata.NewOutgoingContext(fwdCtx, md)
+	}
+	receipt, err := call(client, fwdCtx, in)
+	if err != nil {
+		print("could not forward admin request to %s; err=%s", leader, err.Error())
+		return &intrigue.Receipt{Error: "cluster.leaderUnavailable"}
+	}
+	return receipt
+}
+
+// awaitService waits for a registration made through the leader to be applied to this core,
+// so that the service can be routed to as soon as it has its receipt
+func (c *Cluster) awaitService(name, fingerprint string) {
+	deadline := time.Now().Add(clusterApplyTimeout)
+	for time.Now().Before(deadline) {
+		if s, err := c.router.LookupService(name); err == nil && s.record().Fingerprint == fingerprint {
+			return
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	print("registration of %s was not replicated in time", name)
+}
+
+// Shutdown hands leadership to another core if this one has it and stops raft
+func (c *Cluster) Shutdown() {
+	if c.IsLeader() {
+		if err := c.raft.LeadershipTransfer().Error(); err != nil {
+			print("could not transfer leadership; err=%s", err.Error())
+		}
+	}
+	if err := c.raft.Shutdown().Error(); err != nil {
+		print("could not shut down raft; err=%s", err.Error())
+	}
+	c.store.Close()
+}
+
+/**********************************************************************************
+**** Raft state machine
+**********************************************************************************/
+
+// clusterFSM applies the committed changes to the registry to the router
+type clusterFSM struct {
+	router *Router
+}
+
+func (f *clusterFSM) Apply(l *raft.Log) interface{} {
+	entry := &walEntry{}
+	if err := json.Unmarshal(l.Data, entry); err != nil || entry.Service == nil {
+		print("cluster: dropping unreadable log entry at index %d", l.Index)
+		return nil
+	}
+	switch entry.Op {
+	case "put":
+		f.router.apply(entry.Service)
+	case "remove":
+		f.router.remove(entry.Service.Name)
+	}
+	return nil
+}
+
+func (f *clusterFSM) Snapshot() (raft.FSMSnapshot, error) {
+	records, counter := f.router.records()
+	return &clusterSnapshot{Counter: counter, Services: records}, nil
+}
+
+func (f *clusterFSM) Restore(rc io.ReadCloser) error {
+	defer rc.Close()
+	snap := registrySnapshot{}
+	if err := json.NewDecoder(rc).Decode(&snap); err != nil {
+		return err
+	}
+	f.router.reset(snap.Services)
+	f.router.setIDCounter(snap.Counter)
+	for _, rec := range snap.Services {
+		f.router.apply(rec)
+	}
+	return nil
+}
+
+// clusterSnapshot is written in the same format as the snapshot of the local registry
+type clusterSnapshot registrySnapshot
+
+func (s *clusterSnapshot) Persist(sink raft.SnapshotSink) error {
+	if err := json.NewEncoder(sink).Encode((*registrySnapshot)(s)); err != nil {
+		sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+func (s *clusterSnapshot) Release() {}
+
+// raftLog passes the output of raft through to the core log
+type raftLog struct{}
+
+func (l *raftLog) Write(p []byte) (int, error) {
+	print("raft: %s", strings.TrimSpace(string(p)))
+	return len(p), nil
+}
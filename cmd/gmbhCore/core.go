@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -16,6 +18,7 @@ import (
 	"github.com/gmbh-micro/rpc/address"
 	"github.com/gmbh-micro/rpc/intrigue"
 	"github.com/rs/xid"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -40,6 +43,25 @@ type Core struct {
 	// Router controls all aspects of data requests & handling in Core
 	Router *Router
 
+	// metrics counts the traffic passing through core
+	metrics *Metrics
+
+	// limiter applies rate and concurrency limits to forwarded data requests
+	limiter *Limiter
+
+	// compression counts the bytes saved by compressing data requests, both those received
+	// and those forwarded by core
+	compression *rpc.CompressionStats
+
+	// events are pushed to the websocket subscribers
+	events *Events
+
+	// ws serves browser clients, nil if it is not configured
+	ws *WebSocket
+
+	// dns answers queries for the services, nil if it is not configured
+	dns *DNS
+
 	// env is set in the environment and controls the environment that the core is running
 	// in.
 	env string
@@ -64,11 +86,13 @@ func NewCore(cPath, env, addr string, verbose bool) (*Core, error) {
 	}
 
 	var userConfig *config.SystemCore
+	var rules []*config.ACLRule
+	var limits []*config.LimitConfig
+	procmAddress := config.DefaultSystemProcm.Address
 	projpath := ""
 	var err error
 	if cPath == "" {
 		userConfig = config.DefaultSystemCore
-		userConfig.Address = addr
 		projpath = fileutil.Getpwd()
 	} else {
 		userConfig, err = config.ParseSystemCore(cPath)
@@ -76,20 +100,49 @@ func NewCore(cPath, env, addr string, verbose bool) (*Core, error) {
 			print("could not parse config; err=%v", err.Error())
 			return nil, err
 		}
+		rules, err = config.ParseACL(cPath)
+		if err != nil {
+			print("could not parse acl; err=%v", err.Error())
+			return nil, err
+		}
+		limits, err = config.ParseLimits(cPath)
+		if err != nil {
+			print("could not parse limits; err=%v", err.Error())
+			return nil, err
+		}
+		procm, err := config.ParseSystemProcm(cPath)
+		if err == nil && procm != nil {
+			procmAddress = procm.Address
+		}
 		projpath = fileutil.GetAbs(cPath)
 	}
 
-	if env == "C" {
+	auditPath := userConfig.AuditLog
+	if auditPath == "" {
+		auditPath = filepath.Join(projpath, config.LogPath, config.AuditLogName)
+	}
+	metrics := NewMetrics()
+	compression := rpc.NewCompressionStats()
+
+	if addr != "" {
 		userConfig.Address = addr
 	}
 
+	con := rpc.NewCabalConnection(userConfig.Address, &cabalServer{})
+	con.Options = []grpc.ServerOption{grpc.StatsHandler(compression)}
+	con.BindAddress = address.Bind(userConfig.BindHost, userConfig.Address)
+
 	core = &Core{
 		Version:     config.Version,
 		Code:        config.Code,
 		ProjectPath: projpath,
-		con:         rpc.NewCabalConnection(userConfig.Address, &cabalServer{}),
+		con:         con,
 		conf:        userConfig,
-		Router:      NewRouter(),
+		Router:      NewRouter(NewAccessList(rules, auditPath, metrics), userConfig.AdvertiseHost, userConfig.BindHost),
+		metrics:     metrics,
+		limiter:     NewLimiter(limits, metrics),
+		compression: compression,
+		events:      NewEvents(metrics),
 		msgCounter:  1,
 		startTime:   time.Now(),
 		// mode:        os.Getenv("SERVICEMODE"),
@@ -109,6 +162,37 @@ func NewCore(cPath, env, addr string, verbose bool) (*Core, error) {
 	print(" (_| | | | |_) | | \\_ (_) | (/_ |_/ (_|  |_ (_| ")
 	print("  _|                                            ")
 	print("version=%v; code=%v; env=%s, startTime=%s", core.Version, core.Code, core.env, core.startTime.Format(time.Stamp))
+	if core.Router.acl.Enabled() {
+		print("enforcing %d acl rules; audit=%s", len(rules), auditPath)
+	}
+	registryPath := userConfig.Registry
+	if registryPath == "" || registryPath == "none" {
+		registryPath = filepath.Join(projpath, config.RegistryPath)
+	}
+	if len(userConfig.Cluster) != 0 {
+		// each core keeps its own raft log, named after its address so that several can
+		// share a project directory
+		dir := filepath.Join(registryPath, strings.NewReplacer(":", "_", "/", "_").Replace(userConfig.Address))
+		core.Router.cluster, err = NewCluster(userConfig.Address, userConfig.Cluster, dir, core.Router)
+		if err != nil {
+			print("could not start cluster; err=%v", err.Error())
+			return nil, err
+		}
+		print("replicating the registry with %d cores", len(userConfig.Cluster))
+	} else if userConfig.Registry != "none" {
+		registry, err := OpenRegistry(registryPath)
+		if err != nil {
+			print("could not open registry; err=%v", err.Error())
+			return nil, err
+		}
+		core.Router.Restore(registry)
+	}
+	if userConfig.WebSocket != "" {
+		core.ws = NewWebSocket(core, userConfig, procmAddress)
+	}
+	if userConfig.DNS != "" {
+		core.dns = NewDNS(core.Router, userConfig.DNS, userConfig.DNSDomain)
+	}
 	return core, nil
 }
 
@@ -129,6 +213,15 @@ func (c *Core) Start() {
 	}
 	print("connected; address=%s", c.con.Address)
 
+	if c.ws != nil {
+		c.ws.Start()
+	}
+	if c.dns != nil {
+		c.dns.Start()
+	}
+	go c.Router.watchHealth(c.conf.HealthInterval.Duration, c.conf.HealthTimeout.Duration)
+	go c.Router.reapEvery(c.conf.HealthInterval.Duration, c.conf.StaleAfter.Duration, c.conf.ReapAfter.Duration)
+
 	c.Wait()
 }
 
@@ -156,11 +249,26 @@ func (c *Core) Wait() {
 func (c *Core) shutdown(remote bool, source string) {
 	// print("shutdown procedure started from " + source)
 
-	if c.env != "M" {
+	// the services of a cluster carry on with the other cores
+	if c.env != "M" && c.Router.cluster == nil {
 		done := make(chan bool)
 		go c.Router.sendShutdownNotices(done)
 		<-done
 	}
+	c.Router.acl.Close()
+	if c.Router.registry != nil {
+		c.Router.registry.Close()
+	}
+	if c.Router.cluster != nil {
+		c.Router.cluster.Shutdown()
+	}
+	if c.ws != nil {
+		c.ws.Close()
+	}
+	if c.dns != nil {
+		c.dns.Close()
+	}
+	c.con.Disconnect()
 
 	print("shutdown complete...")
 	return
@@ -181,33 +289,276 @@ type Router struct {
 	// map is walked using a range it will return a value for every alias and thus have duplicates
 	serviceNames []string
 
+	// reserved are the names that core makes requests as itself, such as wsSender, that no
+	// service may register with
+	reserved map[string]bool
+
 	// idCounter keeps track of the current runnig id
 	idCounter int
 
 	// addressHandler is in charge of assigning addresses and making sure that there are no collisions
 	addressing *address.Handler
 
+	// acl holds the access control rules that are checked along with the peer groups
+	acl *AccessList
+
+	// registry persists the services across restarts, nil if it is turned off
+	registry *Registry
+
+	// cluster replicates the services to the other cores, nil when core runs on its own
+	cluster *Cluster
+
 	verbose bool
 	mu      *sync.Mutex
 }
 
-// NewRouter instantiates and returns a new Router structure
-func NewRouter() *Router {
+// NewRouter instantiates and returns a new Router structure. Services are assigned addresses
+// on host and listen on bind, the same as host when empty.
+func NewRouter(acl *AccessList, host, bind string) *Router {
 	r := &Router{
 		services:     make(map[string]*GmbhService),
 		serviceNames: make([]string, 0),
+		reserved:     make(map[string]bool),
 		idCounter:    100,
-		addressing:   address.NewHandler(config.Localhost, config.ServicePort, config.ServicePort+1000),
+		addressing:   address.NewHandler(host, config.ServicePort, config.ServicePort+1000),
+		acl:          acl,
 		mu:           &sync.Mutex{},
 		verbose:      true,
 	}
+	r.addressing.SetBindHost(bind)
 	return r
 }
 
+// Restore adds the services persisted in reg to the router with their ids, addresses and
+// fingerprints, and then re-verifies each one that was running. Those that do not answer are
+// marked as failed and are taken over when they register again.
+func (r *Router) Restore(reg *Registry) {
+	r.registry = reg
+
+	records, counter := reg.Records()
+	r.setIDCounter(counter)
+
+	restored := []*GmbhService{}
+	for _, rec := range records {
+		s := serviceFromRecord(rec)
+		if err := r.addToMap(s); err != nil {
+			print("could not restore service=%s; err=%s", s.String(), err.Error())
+			continue
+		}
+		// shut down services gave up their address and claim it back when they return
+		if s.State != Shutdown {
+			r.addressing.Reserve(s.Address)
+		}
+		applyLimit(s)
+		restored = append(restored, s)
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range restored {
+		if s.State != Running {
+			continue
+		}
+		wg.Add(1)
+		go func(s *GmbhService) {
+			defer wg.Done()
+			if !r.CheckIsAlive(s.Address) {
+				s.UpdateState(Failed)
+			}
+		}(s)
+	}
+	wg.Wait()
+	print("restored %d services from the registry", len(restored))
+}
+
+// apply brings the router in line with a record replicated from the leader of the cluster,
+// adding the service if it is new
+func (r *Router) apply(rec *registryRecord) {
+	if id, err := strconv.Atoi(rec.ID); err == nil {
+		r.setIDCounter(id)
+	}
+	if parseState(rec.State) == Shutdown {
+		r.addressing.Release(rec.Address)
+	} else {
+		r.addressing.Reserve(rec.Address)
+	}
+
+	s, err := r.LookupService(rec.Name)
+	if err != nil {
+		s = serviceFromRecord(rec)
+		if err := r.addToMap(s); err != nil {
+			print("could not apply service=%s; err=%s", s.String(), err.Error())
+			return
+		}
+		applyLimit(s)
+		if core != nil {
+			core.events.Publish(topicServices, serviceEvent("registered", s, s.State))
+		}
+		return
+	}
+
+	r.setAliases(s, rec.Aliases)
+
+	// a shut down service released its address already, and while a snapshot is applied
+	// the old address may have been reserved for another service
+	s.mu.Lock()
+	prevState, prevAddr := s.State, s.Address
+	s.mu.Unlock()
+	if prevAddr != rec.Address && prevState != Shutdown && !r.addressTaken(prevAddr, s) {
+		r.addressing.Release(prevAddr)
+	}
+
+	state := parseState(rec.State)
+	s.mu.Lock()
+	changed := s.State != state
+	s.ID = rec.ID
+	s.Address = rec.Address
+	s.PeerGroups = make(map[string]bool)
+	s.setPeerGroups(rec.PeerGroups)
+	s.State = state
+	s.Fingerprint = rec.Fingerprint
+	s.Compression = rec.Compression
+	s.Limit = serviceLimit{Rate: rec.RateLimit, Burst: rec.Burst, MaxInFlight: rec.MaxInFlight}
+	s.Draining = rec.Draining
+	s.mu.Unlock()
+	applyLimit(s)
+	if changed && core != nil {
+		core.events.Publish(topicServices, serviceEvent("state", s, state))
+	}
+}
+
+// setAliases replaces the aliases of s in the router with aliases. An alias that belongs to
+// another service or is reserved is left out.
+func (r *Router) setAliases(s *GmbhService, aliases []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, alias := range s.Aliases {
+		if r.services[alias] == s {
+			delete(r.services, alias)
+		}
+	}
+	kept := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		if alias == "" {
+			continue
+		}
+		if other, ok := r.services[alias]; (ok && other != s) || r.reserved[alias] {
+			print("could not add alias=%s to service=%s, it is already taken", alias, s.Name)
+			continue
+		}
+		r.services[alias] = s
+		kept = append(kept, alias)
+	}
+	s.mu.Lock()
+	s.Aliases = kept
+	s.mu.Unlock()
+}
+
+// addressTaken returns true if a service other than s that has not shut down has address
+func (r *Router) addressTaken(address string, s *GmbhService) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, n := range r.serviceNames {
+		other := r.services[n]
+		if other == s {
+			continue
+		}
+		other.mu.Lock()
+		taken := other.Address == address && other.State != Shutdown
+		other.mu.Unlock()
+		if taken {
+			return true
+		}
+	}
+	return false
+}
+
+// reset readies the router for the records of a snapshot of the cluster. Services that are
+// not in it are removed, and the addresses of the rest are released to be reserved again as
+// the records are applied.
+func (r *Router) reset(records []*registryRecord) {
+	keep := make(map[string]bool, len(records))
+	for _, rec := range records {
+		keep[rec.Name] = true
+	}
+
+	r.mu.Lock()
+	services := make([]*GmbhService, 0, len(r.serviceNames))
+	for _, n := range r.serviceNames {
+		services = append(services, r.services[n])
+	}
+	r.mu.Unlock()
+
+	for _, s := range services {
+		if !keep[s.Name] {
+			r.remove(s.Name)
+			continue
+		}
+		s.mu.Lock()
+		state, address := s.State, s.Address
+		s.mu.Unlock()
+		if state != Shutdown {
+			r.addressing.Release(address)
+		}
+	}
+}
+
+// errUnavailable is returned when a change to a service could not be persisted
+var errUnavailable = errors.New("registry.unavailable")
+
+// persist records the current state of s in the registry, or replicates it to the rest of
+// the cluster. The change is durable once it returns without error.
+func (r *Router) persist(s *GmbhService) error {
+	var err error
+	switch {
+	case r.cluster != nil:
+		err = r.cluster.Put(s.record())
+	case r.registry != nil:
+		err = r.registry.Put(s.record())
+	}
+	if err != nil {
+		print("could not persist service=%s; err=%s", s.String(), err.Error())
+	}
+	return err
+}
+
+// records returns the persisted form of every service
+func (r *Router) records() ([]*registryRecord, int) {
+	r.mu.Lock()
+	names := append([]string{}, r.serviceNames...)
+	counter := r.idCounter
+	r.mu.Unlock()
+
+	ret := make([]*registryRecord, 0, len(names))
+	for _, n := range names {
+		if s, err := r.LookupService(n); err == nil {
+			ret = append(ret, s.record())
+		}
+	}
+	return ret, counter
+}
+
+// setIDCounter moves the id counter up to id so that it is not assigned again
+func (r *Router) setIDCounter(id int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id > r.idCounter {
+		r.idCounter = id
+	}
+}
+
+// reserveName keeps services from registering with name
+func (r *Router) reserveName(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reserved[name] = true
+}
+
 // LookupService looks through the services map and returns the service if it exists
 func (r *Router) LookupService(name string) (*GmbhService, error) {
 	// r.v("looking up %s", name)
+	r.mu.Lock()
 	retrievedService := r.services[name]
+	r.mu.Unlock()
 	if retrievedService == nil {
 		// print("%s not found in router", name)
 		return nil, errors.New("router.LookupService.NotFound")
@@ -216,63 +567,163 @@ func (r *Router) LookupService(name string) (*GmbhService, error) {
 	return retrievedService, nil
 }
 
-// AddService attaches a service to gmbH
-func (r *Router) AddService(name string, aliases []string, peerGroups []string, env, addr string) (*GmbhService, error) {
-
-	newAddr := addr
-	if env != "C" {
-		var err error
-		newAddr, err = r.addressing.NextAddress()
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	newService := NewService(
-		r.assignNextID(),
-		name,
-		aliases,
-		newAddr,
-		peerGroups,
-	)
+// AddService attaches a service to gmbH. Outside of containers core assigns the address,
+// on port if it is free and otherwise on the next free port. The registration is persisted
+// before it returns, and undone if it cannot be.
+func (r *Router) AddService(name string, aliases []string, peerGroups []string, env, addr string, port int, compression string, lim serviceLimit) (*GmbhService, error) {
 
 	// check to see if it exists in map already
 	s, err := r.LookupService(name)
 	if err == nil {
 		// r.v("found new service already in map")
-		if s.State == Shutdown {
+		s.mu.Lock()
+		state, old := s.State, s.Address
+		s.mu.Unlock()
+		if state == Shutdown {
 			print("correct params reported for this service to assume role of one found")
-			s.UpdateState(Running)
+			// the address was released at shutdown and may have been handed out since
+			if env != "C" {
+				if err := r.reclaimAddress(s, port); err != nil {
+					return nil, err
+				}
+			}
+			if err := r.takeOver(s, compression, lim); err != nil {
+				if env != "C" {
+					s.mu.Lock()
+					r.addressing.Release(s.Address)
+					s.Address = old
+					s.mu.Unlock()
+				}
+				return nil, err
+			}
 			return s, nil
 		}
 		alive := r.CheckIsAlive(s.Address)
 		if !alive {
 			print("could not get a response from service on file, treating new service as one found")
-			s.UpdateState(Running)
+			if err := r.takeOver(s, compression, lim); err != nil {
+				return nil, err
+			}
 			return s, nil
 		}
 		print("service in map reporting still alive; naming err; not adding new service")
 		return nil, fmt.Errorf("duplicate service")
 	}
 
+	newAddr := addr
+	if env != "C" {
+		newAddr, err = r.assignAddress(port)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	newService := NewService(
+		r.assignNextID(),
+		name,
+		aliases,
+		newAddr,
+		peerGroups,
+	)
+	newService.Compression = compression
+	newService.Limit = lim
+
 	err = r.addToMap(newService)
 	if err != nil {
 		print(newService.String())
 		print("could not add service to map; err=%s", err.Error())
+		r.addressing.Release(newAddr)
 		return nil, err
 	}
 
+	if err := r.persist(newService); err != nil {
+		r.unmap(newService.Name)
+		r.addressing.Release(newAddr)
+		return nil, errUnavailable
+	}
+
 	print("added service=%s", newService.String())
 	return newService, nil
 }
 
+// takeOver marks the service on file as running for the one that registered in its place.
+// It is left as it was if the change cannot be persisted.
+func (r *Router) takeOver(s *GmbhService, compression string, lim serviceLimit) error {
+	s.mu.Lock()
+	state, changed, comp, prevLim := s.State, s.Changed, s.Compression, s.Limit
+	s.State = Running
+	s.Compression = compression
+	s.Limit = lim
+	if state != Running {
+		s.Changed = time.Now()
+	}
+	s.mu.Unlock()
+
+	if err := r.persist(s); err != nil {
+		s.mu.Lock()
+		s.State, s.Changed, s.Compression, s.Limit = state, changed, comp, prevLim
+		s.mu.Unlock()
+		return errUnavailable
+	}
+
+	s.setHealth(Health{Live: true, Ready: true})
+	if state != Running {
+		print("marking %s(%s) as %s", s.Name, s.ID, Running.String())
+		if core != nil {
+			core.events.Publish(topicServices, serviceEvent("state", s, Running))
+		}
+	}
+	return nil
+}
+
+// assignAddress returns an address on the preferred port if it is free, otherwise the next
+// free address. A port of zero has no preference.
+func (r *Router) assignAddress(port int) (string, error) {
+	if port != 0 {
+		addr, err := r.addressing.Claim(port)
+		if err == nil {
+			return addr, nil
+		}
+		print("could not assign preferred port=%d; err=%s", port, err.Error())
+	}
+	return r.addressing.NextAddress()
+}
+
+// reclaimAddress gives s back the address it had before it shut down, or a new one if that
+// has since been taken
+func (r *Router) reclaimAddress(s *GmbhService, port int) error {
+	s.mu.Lock()
+	old := s.Address
+	s.mu.Unlock()
+
+	if p, ok := r.addressing.Port(old); ok && (port == 0 || port == p) {
+		if _, err := r.addressing.Claim(p); err == nil {
+			return nil
+		}
+	}
+	addr, err := r.assignAddress(port)
+	if err != nil {
+		return err
+	}
+	print("moving %s(%s) from %s to %s", s.Name, s.ID, old, addr)
+	s.mu.Lock()
+	s.Address = addr
+	s.mu.Unlock()
+	return nil
+}
+
 // Verify a ping
 func (r *Router) Verify(name, fp string) error {
+	r.mu.Lock()
 	s := r.services[name]
+	r.mu.Unlock()
 	if s == nil {
 		return errors.New("verify.notFound")
 	}
-	if r.services[name].Fingerprint != fp {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Fingerprint != fp {
 		return errors.New("verify.fingerprintMismatch")
 	}
 	if s.State == Shutdown {
@@ -287,21 +738,26 @@ func (r *Router) Verify(name, fp string) error {
 // the map
 func (r *Router) addToMap(newService *GmbhService) error {
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.reserved[newService.Name] {
+		print("could not add to map, reserved name")
+		return errors.New("router.addToMap: name is reserved")
+	}
+
 	if _, ok := r.services[newService.Name]; ok {
 		print("could not add to map, duplicate name")
 		return errors.New("router.addToMap: duplicate service with same name found")
 	}
 
 	for _, alias := range newService.Aliases {
-		if _, ok := r.services[alias]; ok {
+		if _, ok := r.services[alias]; ok || r.reserved[alias] {
 			print("could not add to map, duplicate alias=" + alias)
 			return errors.New("router.addToMap: duplicate service with same alias found")
 		}
 	}
 
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	r.services[newService.Name] = newService
 	r.serviceNames = append(r.serviceNames, newService.Name)
 	for _, alias := range newService.Aliases {
@@ -317,12 +773,19 @@ func (r *Router) addToMap(newService *GmbhService) error {
 
 // sendShutdownNotices sends a notice to all clients that core is shutting down
 func (r *Router) sendShutdownNotices(done chan bool) {
+	r.mu.Lock()
+	names := append([]string{}, r.serviceNames...)
+	r.mu.Unlock()
+
 	var wg sync.WaitGroup
-	for _, name := range r.serviceNames {
+	for _, name := range names {
 		wg.Add(1)
 		go func(n string) {
 			defer wg.Done()
-			service := r.services[n]
+			service, err := r.LookupService(n)
+			if err != nil {
+				return
+			}
 			// print("sending shutdown to %s at %s", service.Name, service.Address)
 			client, ctx, can, err := rpc.GetCabalRequest(service.Address, time.Millisecond*500)
 			if err != nil {
@@ -365,8 +828,28 @@ func (r *Router) CheckIsAlive(addr string) bool {
 // fingerprint for validation
 func (r *Router) GetCoreServiceData(core *intrigue.CoreService) []*intrigue.CoreService {
 	ret := []*intrigue.CoreService{core}
-	for _, n := range r.serviceNames {
-		service := r.services[n]
+	r.mu.Lock()
+	names := append([]string{}, r.serviceNames...)
+	r.mu.Unlock()
+	for _, n := range names {
+		service, err := r.LookupService(n)
+		if err != nil {
+			continue
+		}
+
+		// services that are not running are listed without waiting on them to time out
+		service.mu.Lock()
+		state := service.State
+		service.mu.Unlock()
+		if state != Running {
+			ret = append(ret, &intrigue.CoreService{
+				Name:    n,
+				Address: service.Address,
+				Errors:  []string{"not running; state=" + state.String()},
+			})
+			continue
+		}
+
 		// print("sending summary request to %s at %s", service.Name, service.Address)
 		client, ctx, can, err := rpc.GetCabalRequest(service.Address, time.Second*1)
 		if err != nil {
@@ -392,16 +875,28 @@ func (r *Router) GetCoreServiceData(core *intrigue.CoreService) []*intrigue.Core
 			ret = append(ret, &intrigue.CoreService{
 				Name:   n,
 				Errors: []string{"could not contact, err=" + resp.GetError()},
+				Health: service.healthProto(),
 			})
 			continue
 		}
+		// the health is what core last saw, as that decides whether it is routed to
+		for _, cs := range resp.Services {
+			if cs.GetName() == n {
+				cs.Health = service.healthProto()
+			}
+		}
 		ret = append(ret, resp.Services...)
 	}
 	return ret
 }
 
+// errViaCore is returned by GrantPermissions when from may call to, but only through core
+var errViaCore = errors.New("viaCore")
+
 // GrantPermissions checks the peer groups of from and to; If they have a common element,
-// then permission for them to speek is granted, else error
+// and the acl allows from to call every method of to, then permission for them to speek
+// directly is granted. If the acl only allows some methods errViaCore is returned so that
+// each request is checked by core, else error
 func (r *Router) GrantPermissions(from, to string) (string, error) {
 	fromserv, err := r.LookupService(from)
 	if err != nil {
@@ -413,18 +908,54 @@ func (r *Router) GrantPermissions(from, to string) (string, error) {
 		return "", err
 	}
 
-	for k := range fromserv.PeerGroups {
-		if serv.PeerGroups[k] {
-			return serv.Address, nil
+	if !sharePeerGroup(fromserv, serv) {
+		return "", fmt.Errorf("denied")
+	}
+
+	if !r.acl.AllowedAll(fromserv.Name, serv) {
+		return "", errViaCore
+	}
+	return serv.Address, nil
+}
+
+// GrantMethod checks that from is allowed to call method on to through core. The peer
+// groups must have a common element and the acl must allow the method.
+func (r *Router) GrantMethod(from, to, method string) error {
+	fromserv, err := r.LookupService(from)
+	if err != nil {
+		return err
+	}
+
+	serv, err := r.LookupService(to)
+	if err != nil {
+		return err
+	}
+
+	if !sharePeerGroup(fromserv, serv) {
+		return fmt.Errorf("denied")
+	}
+
+	if !r.acl.Allowed(fromserv.Name, serv, method) {
+		r.acl.Deny(fromserv.Name, serv.Name, method, "data")
+		return fmt.Errorf("denied")
+	}
+	return nil
+}
+
+// sharePeerGroup returns true if a and b have at least one peer group in common
+func sharePeerGroup(a, b *GmbhService) bool {
+	bpg := b.peerGroups()
+	for k := range a.peerGroups() {
+		if bpg[k] {
+			return true
 		}
 	}
-	return "", fmt.Errorf("denied")
+	return false
 }
 
 func (r *Router) assignNextID() string {
-	mu := &sync.Mutex{}
-	mu.Lock()
-	defer mu.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.idCounter++
 	return strconv.Itoa(r.idCounter)
 }
@@ -459,12 +990,47 @@ type GmbhService struct {
 	// The last time a ping was received
 	LastPing time.Time
 
+	// The time that the state last changed
+	Changed time.Time
+
 	// assigned by the server, the fingerprint is sent with each ping to verify id
 	Fingerprint string
 
+	// Compression is the algorithm agreed on at registration for data requests forwarded to
+	// the service, empty for none
+	Compression string
+
+	// Limit is the rate and concurrency limit asked for at registration, zero for none
+	Limit serviceLimit
+
+	// Health is the result of the last health check
+	Health Health
+
+	// Draining is set by an admin to stop routing requests to the service without
+	// removing it
+	Draining bool
+
 	mu *sync.Mutex
 }
 
+// serviceLimit is the limit that a service asks core to apply to the requests sent to it
+type serviceLimit struct {
+	Rate        float64
+	Burst       int
+	MaxInFlight int
+}
+
+// applyLimit hands the limit that s asked for at registration to the limiter of core
+func applyLimit(s *GmbhService) {
+	if core == nil {
+		return
+	}
+	s.mu.Lock()
+	lim := s.Limit
+	s.mu.Unlock()
+	core.limiter.SetTargetLimit(s.Name, lim.Rate, lim.Burst, lim.MaxInFlight)
+}
+
 func (g *GmbhService) String() string {
 	return fmt.Sprintf("name=%s; id=%s; address=%s;", g.Name, g.ID, g.Address)
 }
@@ -479,28 +1045,113 @@ func NewService(id string, name string, aliases []string, address string, peerGr
 		PeerGroups:  make(map[string]bool),
 		Added:       time.Now(),
 		State:       Running,
-		LastPing:    time.Now().Add(time.Hour),
+		LastPing:    time.Now(),
+		Changed:     time.Now(),
 		Fingerprint: xid.New().String(),
+		Health:      Health{Live: true, Ready: true},
 		mu:          &sync.Mutex{},
 	}
 	serv.setPeerGroups(peerGroups)
 	return serv
 }
 
+// serviceFromRecord returns the service from its persisted form
+func serviceFromRecord(rec *registryRecord) *GmbhService {
+	s := &GmbhService{
+		ID:          rec.ID,
+		Name:        rec.Name,
+		Aliases:     rec.Aliases,
+		Address:     rec.Address,
+		PeerGroups:  make(map[string]bool),
+		Added:       rec.Added,
+		State:       parseState(rec.State),
+		LastPing:    time.Now(),
+		Changed:     time.Now(),
+		Fingerprint: rec.Fingerprint,
+		Compression: rec.Compression,
+		Limit:       serviceLimit{Rate: rec.RateLimit, Burst: rec.Burst, MaxInFlight: rec.MaxInFlight},
+		Draining:    rec.Draining,
+		Health:      Health{Live: true, Ready: true},
+		mu:          &sync.Mutex{},
+	}
+	s.setPeerGroups(rec.PeerGroups)
+	return s
+}
+
+// record returns the persisted form of the service
+func (g *GmbhService) record() *registryRecord {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	pg := make([]string, 0, len(g.PeerGroups))
+	for k := range g.PeerGroups {
+		pg = append(pg, k)
+	}
+	return &registryRecord{
+		ID:          g.ID,
+		Name:        g.Name,
+		Aliases:     g.Aliases,
+		Address:     g.Address,
+		PeerGroups:  pg,
+		Added:       g.Added,
+		State:       g.State.String(),
+		Fingerprint: g.Fingerprint,
+		Compression: g.Compression,
+		RateLimit:   g.Limit.Rate,
+		Burst:       g.Limit.Burst,
+		MaxInFlight: g.Limit.MaxInFlight,
+		Draining:    g.Draining,
+	}
+}
+
 func (g *GmbhService) setPeerGroups(pg []string) {
 	for _, v := range pg {
 		g.PeerGroups[v] = true
 	}
 }
 
-// UpdateState of the current state of the service
-func (g *GmbhService) UpdateState(s State) {
+// aliases returns a copy of the aliases of the service
+func (g *GmbhService) aliases() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string{}, g.Aliases...)
+}
+
+// peerGroups returns a copy of the peer groups of the service, which are replaced when a
+// record is replicated from the leader
+func (g *GmbhService) peerGroups() map[string]bool {
 	g.mu.Lock()
 	defer g.mu.Unlock()
-	if s != g.State {
+	pg := make(map[string]bool, len(g.PeerGroups))
+	for k, v := range g.PeerGroups {
+		pg[k] = v
+	}
+	return pg
+}
+
+// UpdateState of the current state of the service. The change is undone and the error
+// returned if it cannot be persisted.
+func (g *GmbhService) UpdateState(s State) error {
+	g.mu.Lock()
+	prev, prevChanged := g.State, g.Changed
+	changed := s != g.State
+	if changed {
 		print("marking %s(%s) as %s", g.Name, g.ID, s.String())
 		g.State = s
+		g.Changed = time.Now()
 	}
+	g.mu.Unlock()
+	if !changed || core == nil {
+		return nil
+	}
+
+	if err := core.Router.persist(g); err != nil {
+		g.mu.Lock()
+		g.State, g.Changed = prev, prevChanged
+		g.mu.Unlock()
+		return err
+	}
+	core.events.Publish(topicServices, serviceEvent("state", g, s))
+	return nil
 }
 
 // State controls the state of a remote server
@@ -529,3 +1180,13 @@ func (s State) String() string {
 	}
 	return "%!State()"
 }
+
+// parseState is the reverse of State.String, unknown states are read as Failed
+func parseState(s string) State {
+	for i, name := range states {
+		if name == s {
+			return State(i + 1)
+		}
+	}
+	return Failed
+}
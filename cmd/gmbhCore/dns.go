@@ -0,0 +1,186 @@
+package main
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gmbh-micro/rpc"
+	"github.com/miekg/dns"
+)
+
+// dnsTTL is kept short as services can move whenever they register again
+const dnsTTL = 5
+
+// DNS answers A, AAAA and SRV queries for the services in the router so that tools that cannot
+// use WhoIs can still find them. A service named payments, or with the alias payments, is found
+// at payments.<domain> and _payments._tcp.<domain>. Only services that are running and ready
+// are answered.
+type DNS struct {
+	router  *Router
+	address string
+
+	// domain is fully qualified, such as "gmbh.local."
+	domain string
+
+	servers []*dns.Server
+}
+
+// NewDNS returns a dns server for the services in router, it is not started until Start is
+// called
+func NewDNS(router *Router, address, domain string) *DNS {
+	return &DNS{
+		router:  router,
+		address: address,
+		domain:  dns.Fqdn(strings.ToLower(domain)),
+	}
+}
+
+// Start serving over udp and tcp in new goroutines
+func (d *DNS) Start() {
+	for _, network := range []string{"udp", "tcp"} {
+		s := &dns.Server{Addr: d.address, Net: network, Handler: d}
+		d.servers = append(d.servers, s)
+		go func(s *dns.Server) {
+			if err := s.ListenAndServe(); err != nil {
+				print("dns server closed; net=%s; err=%s", s.Net, err.Error())
+			}
+		}(s)
+	}
+	print("dns started; address=%s; domain=%s", d.address, d.domain)
+}
+
+// Close the servers
+func (d *DNS) Close() {
+	for _, s := range d.servers {
+		s.Shutdown()
+	}
+}
+
+// ServeDNS answers the query in req
+func (d *DNS) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	resp := new(dns.Msg)
+	resp.SetReply(req)
+	resp.Authoritative = true
+
+	if len(req.Question) != 1 {
+		resp.SetRcode(req, dns.RcodeFormatError)
+		w.WriteMsg(resp)
+		return
+	}
+	q := req.Question[0]
+	qname := strings.ToLower(q.Name)
+	if !dns.IsSubDomain(d.domain, qname) {
+		resp.SetRcode(req, dns.RcodeRefused)
+		w.WriteMsg(resp)
+		return
+	}
+
+	s := d.lookup(strings.TrimSuffix(qname, "."+d.domain))
+	if s == nil {
+		resp.SetRcode(req, dns.RcodeNameError)
+		w.WriteMsg(resp)
+		return
+	}
+
+	s.mu.Lock()
+	address := s.Address
+	s.mu.Unlock()
+
+	// services on unix sockets have no address that dns can give
+	if rpc.IsUnix(address) {
+		resp.SetRcode(req, dns.RcodeNameError)
+		w.WriteMsg(resp)
+		return
+	}
+
+	host, p, err := net.SplitHostPort(address)
+	if err != nil {
+		print("dns: could not parse address=%s of %s", address, s.Name)
+		resp.SetRcode(req, dns.RcodeServerFailure)
+		w.WriteMsg(resp)
+		return
+	}
+	port, _ := strconv.Atoi(p)
+	ips := resolveHost(host)
+
+	switch q.Qtype {
+	case dns.TypeA, dns.TypeAAAA:
+		resp.Answer = addressRecords(q.Name, ips, q.Qtype)
+	case dns.TypeANY:
+		resp.Answer = append(addressRecords(q.Name, ips, dns.TypeA), addressRecords(q.Name, ips, dns.TypeAAAA)...)
+	case dns.TypeSRV:
+		// the target is the plain name of the service, whose addresses go in the extra section
+		target := strings.ToLower(s.Name) + "." + d.domain
+		resp.Answer = []dns.RR{&dns.SRV{
+			Hdr:      dns.RR_Header{Name: q.Name, Rrtype: dns.TypeSRV, Class: dns.ClassINET, Ttl: dnsTTL},
+			Priority: 0,
+			Weight:   0,
+			Port:     uint16(port),
+			Target:   target,
+		}}
+		resp.Extra = append(addressRecords(target, ips, dns.TypeA), addressRecords(target, ips, dns.TypeAAAA)...)
+	}
+	w.WriteMsg(resp)
+}
+
+// lookup returns the running service with the name or alias in label, which is matched
+// without regard to case as dns does. SRV style labels such as _payments._tcp are accepted.
+func (d *DNS) lookup(label string) *GmbhService {
+	if strings.HasPrefix(label, "_") {
+		label = strings.TrimPrefix(label, "_")
+		label = strings.TrimSuffix(strings.TrimSuffix(label, "._tcp"), "._udp")
+	}
+	if strings.Contains(label, ".") {
+		return nil
+	}
+
+	s, err := d.router.LookupService(label)
+	if err != nil {
+		d.router.mu.Lock()
+		for name, candidate := range d.router.services {
+			if strings.EqualFold(name, label) {
+				s = candidate
+				break
+			}
+		}
+		d.router.mu.Unlock()
+	}
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.State != Running || !s.Health.Live || !s.Health.Ready || s.Draining {
+		return nil
+	}
+	return s
+}
+
+// resolveHost returns the ips of host, which is most often already an ip or localhost
+func resolveHost(host string) []net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		print("dns: could not resolve host=%s; err=%s", host, err.Error())
+		return nil
+	}
+	return ips
+}
+
+// addressRecords returns an A or AAAA record for name for each ip of that family
+func addressRecords(name string, ips []net.IP, qtype uint16) []dns.RR {
+	rrs := []dns.RR{}
+	for _, ip := range ips {
+		hdr := dns.RR_Header{Name: name, Rrtype: qtype, Class: dns.ClassINET, Ttl: dnsTTL}
+		if v4 := ip.To4(); v4 != nil && qtype == dns.TypeA {
+			rrs = append(rrs, &dns.A{Hdr: hdr, A: v4})
+		} else if v4 == nil && qtype == dns.TypeAAAA {
+			rrs = append(rrs, &dns.AAAA{Hdr: hdr, AAAA: ip})
+		}
+	}
+	return rrs
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// topicServices carries the registration and state changes of services
+	topicServices = "services"
+
+	// topicProcesses carries the status changes of the processes run by procm
+	topicProcesses = "processes"
+)
+
+// topics that may be subscribed to
+var topics = map[string]bool{
+	topicServices:  true,
+	topicProcesses: true,
+}
+
+// Event is pushed to every subscriber of its topic
+type Event struct {
+	Topic string      `json:"topic"`
+	Time  string      `json:"time"`
+	Data  interface{} `json:"data"`
+}
+
+// Events fans out the events published in core to the subscribers of each topic.
+// Subscribers that do not keep up miss events rather than hold up core.
+type Events struct {
+	// subscribers map [topic][subscription]
+	subscribers map[string]map[*subscription]bool
+
+	metrics *Metrics
+	mu      *sync.Mutex
+}
+
+type subscription struct {
+	events chan Event
+}
+
+// NewEvents returns a hub without subscribers
+func NewEvents(metrics *Metrics) *Events {
+	return &Events{
+		subscribers: make(map[string]map[*subscription]bool),
+		metrics:     metrics,
+		mu:          &sync.Mutex{},
+	}
+}
+
+// Subscribe returns a channel receiving the events of topic and a function to call to stop
+func (e *Events) Subscribe(topic string) (<-chan Event, func()) {
+	sub := &subscription{events: make(chan Event, 32)}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.subscribers[topic] == nil {
+		e.subscribers[topic] = make(map[*subscription]bool)
+	}
+	e.subscribers[topic][sub] = true
+
+	once := &sync.Once{}
+	return sub.events, func() {
+		once.Do(func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			delete(e.subscribers[topic], sub)
+			close(sub.events)
+		})
+	}
+}
+
+// Publish sends data to the subscribers of topic
+func (e *Events) Publish(topic string, data interface{}) {
+	if e == nil {
+		return
+	}
+	ev := Event{
+		Topic: topic,
+		Time:  time.Now().Format(time.RFC3339),
+		Data:  data,
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for sub := range e.subscribers[topic] {
+		select {
+		case sub.events <- ev:
+		default:
+			e.metrics.Inc("events.dropped")
+		}
+	}
+	e.metrics.Inc("events." + topic)
+}
+
+// Subscribers returns the number of subscribers of topic
+func (e *Events) Subscribers(topic string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.subscribers[topic])
+}
+
+// serviceEvent is the data of an event on the services topic
+func serviceEvent(event string, s *GmbhService, state State) map[string]string {
+	return map[string]string{
+		"event":   event,
+		"name":    s.Name,
+		"id":      s.ID,
+		"address": s.Address,
+		"state":   state.String(),
+	}
+}
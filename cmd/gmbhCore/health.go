@@ -0,0 +1,149 @@
+package main
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gmbh-micro/rpc"
+	"github.com/gmbh-micro/rpc/intrigue"
+)
+
+// Health of a service as reported by its health check. Services are taken to be live and
+// ready until they are first checked.
+type Health struct {
+	Live    bool
+	Ready   bool
+	Message string
+	Details map[string]string
+
+	// Checked is when the health was reported, zero if it has not been checked yet
+	Checked time.Time
+}
+
+// watchHealth polls the health check of every running service each interval
+func (r *Router) watchHealth(interval, timeout time.Duration) {
+	for range time.Tick(interval) {
+		r.checkHealth(timeout)
+	}
+}
+
+// checkHealth polls the running and failed services at once and waits for all of them. A
+// running service that reports it is not live is marked as failed, and a failed service that
+// answers as live again is marked as running, by the leader in a cluster.
+func (r *Router) checkHealth(timeout time.Duration) {
+	r.mu.Lock()
+	services := make([]*GmbhService, 0, len(r.serviceNames))
+	for _, n := range r.serviceNames {
+		services = append(services, r.services[n])
+	}
+	r.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, s := range services {
+		s.mu.Lock()
+		state, address := s.State, s.Address
+		s.mu.Unlock()
+		if state == Shutdown {
+			continue
+		}
+		wg.Add(1)
+		go func(s *GmbhService) {
+			defer wg.Done()
+			h, reached := pollHealth(address, timeout)
+			if !reached {
+				if state == Running {
+					s.setHealth(h)
+				}
+				return
+			}
+			s.heard()
+			if r.cluster == nil || r.cluster.IsLeader() {
+				switch {
+				case state == Failed && h.Live:
+					s.UpdateState(Running)
+				case state == Running && !h.Live:
+					s.UpdateState(Failed)
+				}
+			}
+			s.setHealth(h)
+		}(s)
+	}
+	wg.Wait()
+}
+
+// pollHealth asks the service at address for its health. A service that cannot be reached is
+// neither live nor ready, and one that does not report its health is both. reached is false
+// if the service could not be contacted.
+func pollHealth(address string, timeout time.Duration) (h Health, reached bool) {
+	client, ctx, can, err := rpc.GetCabalRequest(address, timeout)
+	if err != nil {
+		return Health{Message: "could not contact; " + err.Error(), Checked: time.Now()}, false
+	}
+	defer can()
+	pong, err := client.Alive(ctx, &intrigue.Ping{Time: time.Now().Format(time.Stamp)})
+	if err != nil {
+		return Health{Message: "could not contact; " + err.Error(), Checked: time.Now()}, false
+	}
+	ph := pong.GetHealth()
+	if ph == nil {
+		return Health{Live: true, Ready: true, Checked: time.Now()}, true
+	}
+	return Health{
+		Live:    ph.GetLive(),
+		Ready:   ph.GetReady(),
+		Message: ph.GetMessage(),
+		Details: ph.GetDetails(),
+		Checked: time.Now(),
+	}, true
+}
+
+// heard records that the service answered core
+func (g *GmbhService) heard() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.LastPing = time.Now()
+}
+
+// setHealth stores the result of a health check and publishes it if liveness or readiness
+// have changed
+func (g *GmbhService) setHealth(h Health) {
+	g.mu.Lock()
+	changed := h.Live != g.Health.Live || h.Ready != g.Health.Ready
+	g.Health = h
+	g.mu.Unlock()
+
+	if changed {
+		print("health of %s(%s) is live=%t; ready=%t; message=%s", g.Name, g.ID, h.Live, h.Ready, h.Message)
+		if core != nil {
+			event := serviceEvent("health", g, g.State)
+			event["live"] = strconv.FormatBool(h.Live)
+			event["ready"] = strconv.FormatBool(h.Ready)
+			core.events.Publish(topicServices, event)
+		}
+	}
+}
+
+// Ready returns true if requests may be forwarded to the service, which has to be both live
+// and ready
+func (g *GmbhService) Ready() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.Health.Live && g.Health.Ready
+}
+
+// healthProto returns the health for the summary
+func (g *GmbhService) healthProto() *intrigue.Health {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	h := &intrigue.Health{
+		Live:    g.Health.Live,
+		Ready:   g.Health.Ready,
+		Message: g.Health.Message,
+		Details: g.Health.Details,
+	}
+	if !g.Health.Checked.IsZero() {
+		h.Checked = g.Health.Checked.Format(time.RFC3339)
+	}
+	return h
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gmbh-micro/config"
+)
+
+var (
+	// errRateLimited is returned when a request would exceed a rate limit
+	errRateLimited = errors.New("core.rateLimited")
+
+	// errOverloaded is returned when a request would exceed a max in-flight limit
+	errOverloaded = errors.New("core.overloaded")
+)
+
+// Limiter applies the rate and concurrency limits to the data requests forwarded by core.
+// Limits are kept per target and per sender so that a single chatty service cannot starve
+// the others.
+type Limiter struct {
+	// targets and senders map [name]limit
+	targets map[string]*limit
+	senders map[string]*limit
+
+	// configured holds the names of the targets with limits from the project config. These
+	// are never overridden by the limits that a service asks for at registration
+	configured map[string]bool
+
+	// configuredSenders holds the names of the senders with limits from the project config
+	configuredSenders map[string]bool
+
+	metrics *Metrics
+	mu      *sync.Mutex
+}
+
+// limit is the state of one rate and concurrency limit
+type limit struct {
+	bucket      *tokenBucket
+	maxInFlight int
+	inFlight    int
+}
+
+// NewLimiter returns a limiter enforcing the limits from the project config
+func NewLimiter(confs []*config.LimitConfig, metrics *Metrics) *Limiter {
+	l := &Limiter{
+		targets:           make(map[string]*limit),
+		senders:           make(map[string]*limit),
+		configured:        make(map[string]bool),
+		configuredSenders: make(map[string]bool),
+		metrics:           metrics,
+		mu:                &sync.Mutex{},
+	}
+	for _, c := range confs {
+		if c.Target != "" {
+			l.targets[c.Target] = newLimit(c.Rate, c.Burst, c.MaxInFlight)
+			l.configured[c.Target] = true
+		}
+		if c.Sender != "" {
+			l.senders[c.Sender] = newLimit(c.Rate, c.Burst, c.MaxInFlight)
+			l.configuredSenders[c.Sender] = true
+		}
+	}
+	return l
+}
+
+// SetTargetLimit sets the limit for requests to target as asked for by the service at
+// registration, a zero rate and max in-flight requests clear the limit it asked for before.
+// Limits from the project config take precedence.
+func (l *Limiter) SetTargetLimit(target string, rate float64, burst, maxInFlight int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.configured[target] {
+		return
+	}
+	lim := newLimit(rate, burst, maxInFlight)
+	old, ok := l.targets[target]
+	if !ok {
+		l.targets[target] = lim
+		return
+	}
+	// the requests in flight release the limit they took, so it is changed in place
+	old.bucket, old.maxInFlight = lim.bucket, lim.maxInFlight
+}
+
+// Acquire reserves a slot for a request from sender to target. If no limit would be exceeded
+// the returned function must be called once the request has finished, otherwise one of
+// errRateLimited or errOverloaded is returned.
+func (l *Limiter) Acquire(sender, target string) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t := l.get(l.targets, target)
+	s := l.get(l.senders, sender)
+
+	if t.full() || s.full() {
+		l.metrics.Inc("limit.overloaded")
+		l.metrics.Inc("limit.overloaded." + target)
+		return nil, errOverloaded
+	}
+
+	now := time.Now()
+	if !t.allow(now) || !s.allow(now) {
+		l.metrics.Inc("limit.rateLimited")
+		l.metrics.Inc("limit.rateLimited." + target)
+		return nil, errRateLimited
+	}
+	t.take()
+	s.take()
+
+	t.inFlight++
+	s.inFlight++
+	once := &sync.Once{}
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			t.inFlight--
+			s.inFlight--
+		})
+	}, nil
+}
+
+// Limited returns true if requests from sender or to target have a rate or in-flight limit.
+// Those requests have to go through core for the limit to apply.
+func (l *Limiter) Limited(sender, target string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t, s := l.targets[target], l.senders[sender]
+	return (t != nil && t.limited()) || (s != nil && s.limited())
+}
+
+// Remove drops the limits of a service that has been removed from the router, other than
+// those from the project config. Requests still in flight release their slot as usual.
+func (l *Limiter) Remove(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.configured[name] {
+		delete(l.targets, name)
+	}
+	if !l.configuredSenders[name] {
+		delete(l.senders, name)
+	}
+}
+
+// InFlight returns the number of requests to target that are waiting on a response
+func (l *Limiter) InFlight(target string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t, ok := l.targets[target]; ok {
+		return t.inFlight
+	}
+	return 0
+}
+
+// get returns the limit with name from m, an unlimited one is added if it does not exist
+// so that the in-flight requests are always counted
+func (l *Limiter) get(m map[string]*limit, name string) *limit {
+	lim, ok := m[name]
+	if !ok {
+		lim = newLimit(0, 0, 0)
+		m[name] = lim
+	}
+	return lim
+}
+
+func newLimit(rate float64, burst, maxInFlight int) *limit {
+	lim := &limit{maxInFlight: maxInFlight}
+	if rate > 0 {
+		lim.bucket = newTokenBucket(rate, burst)
+	}
+	return lim
+}
+
+// limited returns true if the limit has a rate or a max in-flight requests
+func (lim *limit) limited() bool {
+	return lim.bucket != nil || lim.maxInFlight > 0
+}
+
+// full returns true if the limit has reached its max in-flight requests
+func (lim *limit) full() bool {
+	return lim.maxInFlight > 0 && lim.inFlight >= lim.maxInFlight
+}
+
+// allow returns true if a token is available
+func (lim *limit) allow(now time.Time) bool {
+	if lim.bucket == nil {
+		return true
+	}
+	lim.bucket.refill(now)
+	return lim.bucket.tokens >= 1
+}
+
+// take removes a token, allow must be called first
+func (lim *limit) take() {
+	if lim.bucket != nil {
+		lim.bucket.tokens--
+	}
+}
+
+// tokenBucket refills at rate tokens per second up to burst tokens
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
@@ -13,9 +13,10 @@ func main() {
 
 	configPath := flag.String("config", "", "the path to the gmbh config file (toml)")
 	verbose := flag.Bool("verbose", false, "print all output to stdOut and stdErr")
+	address := flag.String("address", "", "the address to serve on, overrides the config and picks this core out of a cluster")
 	flag.Parse()
 
-	coreAddr := config.DefaultSystemCore.Address
+	coreAddr := *address
 	env := os.Getenv("ENV")
 	if env == "C" {
 		coreAddr = os.Getenv("CORE")
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"sync"
+)
+
+// Metrics keeps running counters of the traffic that passes through core. The counters are
+// reported along with the core data in summary requests
+type Metrics struct {
+	counters map[string]int64
+	mu       *sync.Mutex
+}
+
+// NewMetrics returns an empty set of counters
+func NewMetrics() *Metrics {
+	return &Metrics{
+		counters: make(map[string]int64),
+		mu:       &sync.Mutex{},
+	}
+}
+
+// Inc increments the counter with name by one
+func (m *Metrics) Inc(name string) {
+	m.Add(name, 1)
+}
+
+// Add adds n to the counter with name
+func (m *Metrics) Add(name string, n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[name] += n
+}
+
+// Get returns the current value of the counter with name
+func (m *Metrics) Get(name string) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[name]
+}
+
+// Snapshot returns a copy of all counters
+func (m *Metrics) Snapshot() map[string]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ret := make(map[string]int64, len(m.counters))
+	for k, v := range m.counters {
+		ret[k] = v
+	}
+	return ret
+}
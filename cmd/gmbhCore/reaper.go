@@ -0,0 +1,113 @@
+package main
+
+import (
+	"time"
+)
+
+// reapEvery runs the reaper each interval. Running services that core has not heard from in
+// stale are marked as failed, and services that have been failed or shut down for longer than
+// grace are removed from the router.
+func (r *Router) reapEvery(interval, stale, grace time.Duration) {
+	for range time.Tick(interval) {
+		r.reap(stale, grace)
+	}
+}
+
+// reap makes one pass over the services
+func (r *Router) reap(stale, grace time.Duration) {
+
+	// the leader decides for the whole cluster, the others apply what it replicates
+	if r.cluster != nil && !r.cluster.IsLeader() {
+		return
+	}
+
+	r.mu.Lock()
+	services := make([]*GmbhService, 0, len(r.serviceNames))
+	for _, n := range r.serviceNames {
+		services = append(services, r.services[n])
+	}
+	r.mu.Unlock()
+
+	now := time.Now()
+	for _, s := range services {
+		s.mu.Lock()
+		state, lastPing, changed := s.State, s.LastPing, s.Changed
+		s.mu.Unlock()
+
+		switch {
+		case state == Running && now.Sub(lastPing) > stale:
+			print("nothing heard from %s(%s) since %s", s.Name, s.ID, lastPing.Format(time.Stamp))
+			s.UpdateState(Failed)
+		case state != Running && now.Sub(changed) > grace:
+			r.evict(s)
+		}
+	}
+}
+
+// evict removes s from the registry, or from every core of the cluster, and then from the
+// router
+func (r *Router) evict(s *GmbhService) error {
+	var err error
+	switch {
+	case r.cluster != nil:
+		// the cluster removes it from the router of each core once it is committed
+		err = r.cluster.Remove(s.record())
+	case r.registry != nil:
+		err = r.registry.Remove(s.record())
+	}
+	if err != nil {
+		print("could not evict service=%s; err=%s", s.String(), err.Error())
+		return err
+	}
+	if r.cluster == nil {
+		r.remove(s.Name)
+	}
+	return nil
+}
+
+// remove the service with name from the router, which frees its name, aliases and address
+func (r *Router) remove(name string) {
+	s := r.unmap(name)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	state, address, desc := s.State, s.Address, s.String()
+	event := serviceEvent("removed", s, state)
+	s.mu.Unlock()
+
+	// the address of a shut down service was released already and may belong to another
+	if state != Shutdown {
+		r.addressing.Release(address)
+	}
+	print("removed service=%s", desc)
+	if core != nil {
+		core.limiter.Remove(s.Name)
+		core.events.Publish(topicServices, event)
+	}
+}
+
+// unmap deletes the name and aliases of the service with name from the router and returns
+// the service, nil if there is none
+func (r *Router) unmap(name string) *GmbhService {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.services[name]
+	if s == nil {
+		return nil
+	}
+	delete(r.services, s.Name)
+	for _, alias := range s.Aliases {
+		if r.services[alias] == s {
+			delete(r.services, alias)
+		}
+	}
+	for i, n := range r.serviceNames {
+		if n == s.Name {
+			r.serviceNames = append(r.serviceNames[:i], r.serviceNames[i+1:]...)
+			break
+		}
+	}
+	return s
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// registrySnapshotName is the file in the registry directory holding every service as of
+	// the last compaction
+	registrySnapshotName = "snapshot.json"
+
+	// registryWALName is the write-ahead log of the changes since the snapshot, one JSON
+	// entry per line
+	registryWALName = "wal.log"
+
+	// registryWALLimit is the number of entries after which the log is folded into the
+	// snapshot
+	registryWALLimit = 256
+)
+
+// Registry persists the services of the router so that the ids, addresses and fingerprints
+// assigned by core survive a restart. Every change is appended to the write-ahead log before
+// it is acknowledged, and the log is folded into a new snapshot once it grows too long.
+type Registry struct {
+	dir string
+
+	// records are the last known copy of each service by name, kept to write the snapshot
+	records map[string]*registryRecord
+
+	// counter is the highest id that has been assigned
+	counter int
+
+	wal     *os.File
+	entries int
+	mu      *sync.Mutex
+}
+
+// registryRecord is the persisted form of a GmbhService
+type registryRecord struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Aliases     []string  `json:"aliases"`
+	Address     string    `json:"address"`
+	PeerGroups  []string  `json:"peerGroups"`
+	Added       time.Time `json:"added"`
+	State       string    `json:"state"`
+	Fingerprint string    `json:"fingerprint"`
+	Compression string    `json:"compression,omitempty"`
+	RateLimit   float64   `json:"rateLimit,omitempty"`
+	Burst       int       `json:"burst,omitempty"`
+	MaxInFlight int       `json:"maxInFlight,omitempty"`
+	Draining    bool      `json:"draining,omitempty"`
+}
+
+// registrySnapshot is the content of the snapshot file
+type registrySnapshot struct {
+	Counter  int               `json:"counter"`
+	Services []*registryRecord `json:"services"`
+}
+
+// walEntry is one line of the write-ahead log
+type walEntry struct {
+	Op      string          `json:"op"`
+	Service *registryRecord `json:"service"`
+}
+
+// OpenRegistry loads the snapshot in dir and replays the write-ahead log over it. A torn last
+// line, from a crash in the middle of a write, is dropped. The log is compacted straight away
+// so that core starts with an empty one.
+func OpenRegistry(dir string) (*Registry, error) {
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	r := &Registry{
+		dir:     dir,
+		records: make(map[string]*registryRecord),
+		mu:      &sync.Mutex{},
+	}
+
+	snap := registrySnapshot{}
+	data, err := ioutil.ReadFile(filepath.Join(dir, registrySnapshotName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return nil, err
+		}
+	}
+	r.counter = snap.Counter
+	for _, rec := range snap.Services {
+		r.apply(&walEntry{Op: "put", Service: rec})
+	}
+
+	f, err := os.Open(filepath.Join(dir, registryWALName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			entry := &walEntry{}
+			if err := json.Unmarshal(scanner.Bytes(), entry); err != nil || entry.Service == nil {
+				print("registry: dropping unreadable log entry")
+				break
+			}
+			r.apply(entry)
+		}
+		f.Close()
+	}
+
+	if err := r.compact(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Records returns the restored services and the highest id that has been assigned
+func (r *Registry) Records() ([]*registryRecord, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ret := make([]*registryRecord, 0, len(r.records))
+	for _, rec := range r.records {
+		ret = append(ret, rec)
+	}
+	return ret, r.counter
+}
+
+// Put records the current state of a service
+func (r *Registry) Put(rec *registryRecord) error {
+	return r.write(&walEntry{Op: "put", Service: rec})
+}
+
+// Remove deletes a service from the registry
+func (r *Registry) Remove(rec *registryRecord) error {
+	return r.write(&walEntry{Op: "remove", Service: rec})
+}
+
+// write appends entry to the log and syncs it before applying it
+func (r *Registry) write(entry *walEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.wal == nil {
+		return errors.New("registry.closed")
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := r.wal.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := r.wal.Sync(); err != nil {
+		return err
+	}
+	r.apply(entry)
+	r.entries++
+
+	if r.entries >= registryWALLimit {
+		return r.compactLocked()
+	}
+	return nil
+}
+
+// apply an entry to the records
+func (r *Registry) apply(entry *walEntry) {
+	switch entry.Op {
+	case "put":
+		r.records[entry.Service.Name] = entry.Service
+		if id, err := strconv.Atoi(entry.Service.ID); err == nil && id > r.counter {
+			r.counter = id
+		}
+	case "remove":
+		delete(r.records, entry.Service.Name)
+	}
+}
+
+// compact writes the records to a new snapshot and truncates the log
+func (r *Registry) compact() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.compactLocked()
+}
+
+func (r *Registry) compactLocked() error {
+
+	snap := registrySnapshot{Counter: r.counter, Services: make([]*registryRecord, 0, len(r.records))}
+	for _, rec := range r.records {
+		snap.Services = append(snap.Services, rec)
+	}
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// the snapshot is replaced by rename so that a crash leaves either the old or the new one
+	path := filepath.Join(r.dir, registrySnapshotName)
+	tmp, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+
+	if r.wal != nil {
+		r.wal.Close()
+	}
+	r.wal, err = os.OpenFile(filepath.Join(r.dir, registryWALName), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	r.entries = 0
+	return nil
+}
+
+// Close compacts the log and closes it
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.wal == nil {
+		return nil
+	}
+	err := r.compactLocked()
+	r.wal.Close()
+	r.wal = nil
+	return err
+}
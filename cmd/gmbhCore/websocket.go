@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gmbh-micro/config"
+	"github.com/gmbh-micro/gmbh"
+	"github.com/gmbh-micro/rpc"
+	"github.com/gmbh-micro/rpc/intrigue"
+	"github.com/gorilla/websocket"
+)
+
+// wsSender is the name that data requests made over the websocket are sent as. It is the
+// name to use in acl rules for browser clients, and no service may register with it.
+const wsSender = "websocket"
+
+// wsMaxCalls is the number of calls that one client may have waiting on a response, calls
+// over it are answered with core.overloaded
+const wsMaxCalls = 32
+
+// WebSocket serves browser clients at /ws. Over one socket a client can make data requests
+// and subscribe to the events published by core.
+//
+// Each message from the client is a JSON object with a "type" of call, subscribe or
+// unsubscribe and an optional "id" that is repeated in the reply. Calls name a "service",
+// "method" and a "data" object; subscriptions name a "topic". Core replies with messages of
+// type result, error, subscribed or unsubscribed and pushes messages of type event.
+type WebSocket struct {
+	core *Core
+
+	address string
+	origins []string
+
+	// allowNoOrigin lets in clients that send no Origin header, which are not browsers
+	allowNoOrigin bool
+
+	// sender is the service that calls are made as, it is only in its own peer groups
+	sender *GmbhService
+
+	// routes is the allow-list of "service/method" patterns that may be called
+	routes []string
+
+	// procmAddress is polled for process status changes while there are subscribers
+	procmAddress string
+
+	server   *http.Server
+	upgrader websocket.Upgrader
+}
+
+// wsMessage is sent in both directions over the socket
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Service string          `json:"service,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Topic   string          `json:"topic,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// NewWebSocket returns a websocket server for c, it is not started until Start is called
+func NewWebSocket(c *Core, conf *config.SystemCore, procmAddress string) *WebSocket {
+	ws := &WebSocket{
+		core:          c,
+		address:       conf.WebSocket,
+		origins:       conf.WebSocketOrigins,
+		allowNoOrigin: conf.WebSocketAllowNoOrigin,
+		routes:        conf.WebSocketRoutes,
+		procmAddress:  procmAddress,
+		sender:        NewService("", wsSender, nil, "", conf.WebSocketPeerGroups),
+	}
+	ws.upgrader = websocket.Upgrader{CheckOrigin: ws.checkOrigin}
+	c.Router.reserveName(wsSender)
+	return ws
+}
+
+// Start serving in a new goroutine
+func (ws *WebSocket) Start() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", ws.serve)
+	ws.server = &http.Server{Addr: ws.address, Handler: mux}
+
+	go func() {
+		err := ws.server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			print("websocket server closed; err=%s", err.Error())
+		}
+	}()
+	go ws.watchProcesses()
+	print("websocket started; address=%s/ws", ws.address)
+}
+
+// Close the server and all connections
+func (ws *WebSocket) Close() {
+	if ws.server != nil {
+		ws.server.Close()
+	}
+}
+
+// checkOrigin allows browsers from the configured origins, clients without an origin are
+// only allowed if the config says so
+func (ws *WebSocket) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return ws.allowNoOrigin
+	}
+	for _, o := range ws.origins {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// wsConn is one client. Writes are only made from the write loop as the socket does not
+// allow concurrent writers.
+type wsConn struct {
+	conn *websocket.Conn
+	out  chan *wsMessage
+	done chan struct{}
+
+	// calls holds a slot for each call waiting on a response
+	calls chan struct{}
+
+	// subs map [topic]unsubscribe
+	subs map[string]func()
+	mu   *sync.Mutex
+}
+
+func (ws *WebSocket) serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := ws.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		print("websocket upgrade failed; err=%s", err.Error())
+		return
+	}
+	conn.SetReadLimit(4 << 20)
+
+	c := &wsConn{
+		conn:  conn,
+		out:   make(chan *wsMessage, 64),
+		done:  make(chan struct{}),
+		calls: make(chan struct{}, wsMaxCalls),
+		subs:  make(map[string]func()),
+		mu:    &sync.Mutex{},
+	}
+	ws.core.metrics.Inc("websocket.connections")
+	print("-> websocket connected; remote=%s", r.RemoteAddr)
+
+	go c.writeLoop()
+	defer func() {
+		close(c.done)
+		c.unsubscribeAll()
+		conn.Close()
+		print("<- websocket closed; remote=%s", r.RemoteAddr)
+	}()
+
+	for {
+		msg := &wsMessage{}
+		err := conn.ReadJSON(msg)
+		if err != nil {
+			// only a bad message leaves the connection usable
+			switch err.(type) {
+			case *json.SyntaxError, *json.UnmarshalTypeError:
+				c.send(&wsMessage{Type: "error", Error: "invalid message"})
+				continue
+			}
+			return
+		}
+
+		switch msg.Type {
+		case "call":
+			select {
+			case c.calls <- struct{}{}:
+				go func(msg *wsMessage) {
+					defer func() { <-c.calls }()
+					c.send(ws.call(msg))
+				}(msg)
+			default:
+				ws.core.metrics.Inc("websocket.overloaded")
+				c.send(&wsMessage{ID: msg.ID, Type: "error", Service: msg.Service, Method: msg.Method, Error: errOverloaded.Error()})
+			}
+		case "subscribe":
+			c.send(ws.subscribe(c, msg))
+		case "unsubscribe":
+			c.unsubscribe(msg.Topic)
+			c.send(&wsMessage{ID: msg.ID, Type: "unsubscribed", Topic: msg.Topic})
+		default:
+			c.send(&wsMessage{ID: msg.ID, Type: "error", Error: "unknown message type"})
+		}
+	}
+}
+
+// send queues msg for the write loop, it is dropped if the connection has closed
+func (c *wsConn) send(msg *wsMessage) {
+	select {
+	case c.out <- msg:
+	case <-c.done:
+	}
+}
+
+func (c *wsConn) writeLoop() {
+	for {
+		select {
+		case msg := <-c.out:
+			c.conn.SetWriteDeadline(time.Now().Add(time.Second * 10))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				c.conn.Close()
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *wsConn) unsubscribe(topic string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cancel, ok := c.subs[topic]; ok {
+		cancel()
+		delete(c.subs, topic)
+	}
+}
+
+func (c *wsConn) unsubscribeAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for topic, cancel := range c.subs {
+		cancel()
+		delete(c.subs, topic)
+	}
+}
+
+func (ws *WebSocket) subscribe(c *wsConn, msg *wsMessage) *wsMessage {
+	if !topics[msg.Topic] {
+		return &wsMessage{ID: msg.ID, Type: "error", Error: "unknown topic"}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.subs[msg.Topic]; !ok {
+		events, cancel := ws.core.events.Subscribe(msg.Topic)
+		c.subs[msg.Topic] = cancel
+		go func() {
+			for ev := range events {
+				data, err := json.Marshal(ev.Data)
+				if err != nil {
+					continue
+				}
+				c.send(&wsMessage{Type: "event", Topic: ev.Topic, Data: data})
+			}
+		}()
+	}
+	return &wsMessage{ID: msg.ID, Type: "subscribed", Topic: msg.Topic}
+}
+
+// call makes a data request through core as the websocket sender and returns the reply
+func (ws *WebSocket) call(msg *wsMessage) *wsMessage {
+	reply := &wsMessage{ID: msg.ID, Type: "error", Service: msg.Service, Method: msg.Method}
+	c := ws.core
+	c.metrics.Inc("websocket.calls")
+
+	if !ws.allowed(msg.Service, msg.Method) {
+		reply.Error = "route.notAllowed"
+		return reply
+	}
+
+	fwd, err := c.Router.LookupService(msg.Service)
+	if err != nil {
+		reply.Error = "service.notFound"
+		return reply
+	}
+
+	if !sharePeerGroup(ws.sender, fwd) {
+		print("<- websocket; mismatch peer groups; -> %s", fwd.Name)
+		reply.Error = "permission.denied"
+		return reply
+	}
+	if !c.Router.acl.Allowed(wsSender, fwd, msg.Method) {
+		c.Router.acl.Deny(wsSender, fwd.Name, msg.Method, "websocket")
+		reply.Error = "permission.denied"
+		return reply
+	}
+
+	pload, err := gmbh.PayloadFromJSON(msg.Data)
+	if err != nil {
+		reply.Error = "data must be a JSON object"
+		return reply
+	}
+
+	resp := c.forward(wsSender, fwd, &intrigue.DataRequest{
+		Request: &intrigue.Request{
+			Tport: &intrigue.Transport{
+				Sender: wsSender,
+				Target: msg.Service,
+				Method: msg.Method,
+			},
+			Pload: pload,
+		},
+	})
+	if resp.GetError() != "" {
+		reply.Error = resp.GetError()
+		return reply
+	}
+	if e := resp.GetResponder().GetErr(); e != "" {
+		reply.Error = e
+		return reply
+	}
+
+	data, err := gmbh.JSONFromPayload(resp.GetResponder().GetPload(), resp.GetResponder().GetCodec())
+	if err != nil {
+		reply.Error = "could not read response"
+		return reply
+	}
+	reply.Type = "result"
+	reply.Data = data
+	return reply
+}
+
+// allowed checks the service and method against the websocket routes
+func (ws *WebSocket) allowed(service, method string) bool {
+	for _, route := range ws.routes {
+		if ok, _ := path.Match(route, service+"/"+method); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// watchProcesses polls procm while there are subscribers to the processes topic and publishes
+// an event for every process whose status, pid or restarts have changed
+func (ws *WebSocket) watchProcesses() {
+	last := make(map[string]string)
+	for {
+		time.Sleep(time.Second * 2)
+		if ws.core.events.Subscribers(topicProcesses) == 0 {
+			continue
+		}
+
+		client, ctx, can, err := rpc.GetControlRequest(ws.procmAddress, time.Second)
+		if err != nil {
+			continue
+		}
+		reply, err := client.Summary(ctx, &intrigue.Action{Request: "summary.all"})
+		can()
+		if err != nil {
+			continue
+		}
+
+		for _, remote := range reply.GetRemotes() {
+			for _, s := range remote.GetServices() {
+				key := remote.GetID() + "/" + s.GetId()
+				status := s.GetStatus() + ";" + strconv.Itoa(int(s.GetPid())) + ";" + strconv.Itoa(int(s.GetRestarts()))
+				if last[key] == status {
+					continue
+				}
+				last[key] = status
+				ws.core.events.Publish(topicProcesses, map[string]interface{}{
+					"remote":   remote.GetName(),
+					"id":       s.GetId(),
+					"name":     s.GetName(),
+					"status":   s.GetStatus(),
+					"pid":      s.GetPid(),
+					"restarts": s.GetRestarts(),
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gmbh-micro/config"
+	"github.com/gmbh-micro/gmbh"
+)
+
+// Gateway serves POST /{service}/{method} and forwards the JSON body as a data request
+// through gmbh. The payload of the response is written back as JSON.
+type Gateway struct {
+	conf   *config.SystemGateway
+	client *gmbh.Client
+}
+
+// NewGateway returns a gateway making its requests through client
+func NewGateway(conf *config.SystemGateway, client *gmbh.Client) *Gateway {
+	return &Gateway{
+		conf:   conf,
+		client: client,
+	}
+}
+
+// response is the body of every reply from the gateway
+type response struct {
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// statusCodes maps the error codes from gmbh to http status codes
+var statusCodes = map[string]int{
+	"service.notFound":                     http.StatusNotFound,
+	"could not find method in service map": http.StatusNotFound,
+	"permission.denied":                    http.StatusForbidden,
+	"core.rateLimited":                     http.StatusTooManyRequests,
+	"core.overloaded":                      http.StatusServiceUnavailable,
+	"service.overloaded":                   http.StatusServiceUnavailable,
+	"service.notReady":                     http.StatusServiceUnavailable,
+	"service.draining":                     http.StatusServiceUnavailable,
+	"data.gmbhUnavailable":                 http.StatusServiceUnavailable,
+
+	// the gateway itself is not registered with core, most likely because it is still
+	// connecting or core has restarted
+	"sender.unverified": http.StatusServiceUnavailable,
+}
+
+func (gw *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	gw.cors(w, r)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		gw.reply(w, http.StatusMethodNotAllowed, response{Error: "method not allowed"})
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		gw.reply(w, http.StatusNotFound, response{Error: "expected /{service}/{method}"})
+		return
+	}
+	service, method := parts[0], parts[1]
+
+	if !gw.allowed(service, method) {
+		print("route not allowed; %s/%s", service, method)
+		gw.reply(w, http.StatusForbidden, response{Error: "route.notAllowed"})
+		return
+	}
+
+	// one byte past the limit is read to tell a body that is too large from one that fits
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, gw.conf.MaxBodySize+1))
+	if err != nil {
+		gw.reply(w, http.StatusBadRequest, response{Error: "could not read body"})
+		return
+	}
+	if int64(len(body)) > gw.conf.MaxBodySize {
+		gw.reply(w, http.StatusRequestEntityTooLarge, response{Error: "body too large"})
+		return
+	}
+
+	payload := gmbh.NewPayload()
+	if len(strings.TrimSpace(string(body))) != 0 {
+		fields := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(body, &fields); err != nil {
+			gw.reply(w, http.StatusBadRequest, response{Error: "body must be a JSON object"})
+			return
+		}
+		if err := payload.Encode(fields); err != nil {
+			gw.reply(w, http.StatusBadRequest, response{Error: err.Error()})
+			return
+		}
+	}
+
+	// errors from gmbh are returned as err, those from the handler of the target service
+	// are only reported in the responder
+	resp, err := gw.client.MakeRequest(service, method, payload)
+	if code := resp.GetError(); err != nil || code != "" {
+		status := errorStatus(code, err == nil)
+		print("%s/%s failed; status=%d; err=%s", service, method, status, code)
+		gw.reply(w, status, response{Error: code})
+		return
+	}
+
+	var data json.RawMessage
+	if p := resp.GetPayload(); p != nil {
+		data, err = gmbh.JSONFromPayload(p.Proto(), p.Codec())
+		if err != nil {
+			gw.reply(w, http.StatusBadGateway, response{Error: err.Error()})
+			return
+		}
+	}
+	gw.reply(w, http.StatusOK, response{Data: data})
+}
+
+// errorStatus returns the http status for the error code of a request. Errors from the
+// handler of the target service mean that it refused or failed the request, while any
+// other error is reported as a bad gateway.
+func errorStatus(code string, fromHandler bool) int {
+	if status, ok := statusCodes[code]; ok {
+		return status
+	}
+	if fromHandler {
+		return http.StatusInternalServerError
+	}
+	if strings.Contains(code, "DeadlineExceeded") {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
+
+// allowed checks the service and method against the routes in the config
+func (gw *Gateway) allowed(service, method string) bool {
+	for _, route := range gw.conf.Routes {
+		if ok, _ := path.Match(route, service+"/"+method); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// cors sets the cors headers if the origin of the request is one of the configured origins
+func (gw *Gateway) cors(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return
+	}
+	for _, o := range gw.conf.CORSOrigins {
+		if o == "*" || o == origin {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.Header().Add("Vary", "Origin")
+			return
+		}
+	}
+}
+
+func (gw *Gateway) reply(w http.ResponseWriter, status int, resp response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
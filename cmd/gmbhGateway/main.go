@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gmbh-micro/config"
+	"github.com/gmbh-micro/gmbh"
+	"github.com/gmbh-micro/notify"
+)
+
+func main() {
+
+	configPath := flag.String("config", "", "the path to the gmbh config file (toml)")
+	address := flag.String("address", "", "the address to serve http on, overrides the config")
+	flag.Parse()
+
+	conf := config.DefaultSystemGateway
+	coreAddr := config.DefaultSystemCore.Address
+	var clusterAddrs []string
+	if *configPath != "" {
+		var err error
+		conf, err = config.ParseSystemGateway(*configPath)
+		if err != nil {
+			print("could not parse config; err=%s", err.Error())
+			os.Exit(1)
+		}
+		core, err := config.ParseSystemCore(*configPath)
+		if err != nil {
+			print("could not parse config; err=%s", err.Error())
+			os.Exit(1)
+		}
+		coreAddr = core.Address
+		for _, p := range core.Cluster {
+			clusterAddrs = append(clusterAddrs, p.Address)
+		}
+	}
+	if os.Getenv("ENV") == "C" {
+		coreAddr = os.Getenv("CORE")
+	}
+	if *address != "" {
+		conf.Address = *address
+	}
+
+	client, err := gmbh.NewClient(
+		gmbh.SetService(gmbh.ServiceOptions{
+			Name:       conf.Name,
+			PeerGroups: conf.PeerGroups,
+		}),
+		gmbh.SetStandalone(gmbh.StandaloneOptions{
+			CoreAddress:   coreAddr,
+			CoreAddresses: clusterAddrs,
+		}),
+	)
+	if err != nil {
+		print("could not create client; err=%s", err.Error())
+		os.Exit(1)
+	}
+	client.Start()
+
+	gw := NewGateway(conf, client)
+	print("serving http; address=%s; routes=%v", conf.Address, conf.Routes)
+	err = http.ListenAndServe(conf.Address, gw)
+	if err != nil {
+		print("http server closed; err=%s", err.Error())
+		client.Shutdown("http")
+	}
+}
+
+func print(format string, a ...interface{}) {
+	if os.Getenv("ENV") == "M" {
+		format = "[" + time.Now().Format(config.LogStamp) + "] [gateway] " + format
+		notify.LnBlueF(format, a...)
+	} else {
+		notify.LnBlueF("[gateway] "+format, a...)
+	}
+}
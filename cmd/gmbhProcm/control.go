@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"io"
 	"strings"
 	"time"
 
@@ -22,8 +23,44 @@ func (c *controlServer) StartService(ctx context.Context, in *intrigue.Action) (
 }
 
 func (c *controlServer) KillService(ctx context.Context, in *intrigue.Action) (*intrigue.Receipt, error) {
+
 	print("<- Kill; action=" + in.String())
-	return &intrigue.Receipt{Error: "request.action.invalid"}, nil
+
+	if in.GetRequest() != "kill.one" {
+		return &intrigue.Receipt{Error: "request.action.unknown"}, nil
+	}
+
+	pm, err := GetProcM()
+	if err != nil {
+		print("internal system error")
+		return &intrigue.Receipt{Error: "internal.pmref"}, nil
+	}
+
+	remote, err := pm.LookupRemote(in.GetRemoteID())
+	if err != nil {
+		print("could not find remote")
+		return &intrigue.Receipt{Error: "remote.notFound"}, nil
+	}
+
+	client, ctx, can, err := rpc.GetRemoteRequest(remote.Address, time.Second*5)
+	if err != nil {
+		print("could not contact " + remote.ID)
+		return &intrigue.Receipt{Error: "remote.unavailable"}, nil
+	}
+	defer can()
+
+	reply, err := client.NotifyAction(ctx, &intrigue.Action{
+		Request: "service.kill.one",
+		Target:  in.GetTarget(),
+	})
+	if err != nil {
+		print("could not contact " + remote.ID)
+		return &intrigue.Receipt{Error: "remote.unavailable"}, nil
+	}
+	if reply.GetError() != "" {
+		return &intrigue.Receipt{Error: reply.GetError()}, nil
+	}
+	return &intrigue.Receipt{Message: reply.GetMessage()}, nil
 }
 
 func (c *controlServer) RestartService(ctx context.Context, in *intrigue.Action) (*intrigue.Receipt, error) {
@@ -199,6 +236,12 @@ func (c *controlServer) UpdateRegistration(ctx context.Context, in *intrigue.Ser
 			return &intrigue.Receipt{Error: "router.err=" + err.Error()}, nil
 		}
 
+		// remotes in containers listen on the address they gave
+		bindHost := pm.bindHost
+		if env == "C" {
+			bindHost = ""
+		}
+
 		print("sent registration response")
 		return &intrigue.Receipt{
 			Message: "registered",
@@ -206,6 +249,7 @@ func (c *controlServer) UpdateRegistration(ctx context.Context, in *intrigue.Ser
 				Address:     address,
 				ID:          id,
 				Fingerprint: fingerprint,
+				BindHost:    bindHost,
 			},
 		}, nil
 
@@ -252,3 +296,48 @@ func remoteToRPC(r *RemoteServer, services []*intrigue.Service) *intrigue.Proces
 		Services: services,
 	}
 }
+
+func (c *controlServer) Logs(in *intrigue.LogRequest, stream intrigue.Control_LogsServer) error {
+
+	print("<- logs; request=" + in.String())
+
+	pm, err := GetProcM()
+	if err != nil {
+		print("internal system error")
+		return stream.Send(&intrigue.LogLine{Error: "internal.pmref"})
+	}
+
+	remote, err := pm.LookupRemote(in.GetRemoteID())
+	if err != nil {
+		print("could not find remote")
+		return stream.Send(&intrigue.LogLine{Error: "remote.notFound"})
+	}
+
+	client, con, err := rpc.DialRemote(remote.Address)
+	if err != nil {
+		print("could not contact " + remote.ID)
+		return stream.Send(&intrigue.LogLine{Error: "remote.unavailable"})
+	}
+	defer con.Close()
+
+	logs, err := client.Logs(stream.Context(), in)
+	if err != nil {
+		print("could not contact " + remote.ID)
+		return stream.Send(&intrigue.LogLine{Error: "remote.unavailable"})
+	}
+	for {
+		line, err := logs.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if stream.Context().Err() != nil {
+				return nil
+			}
+			return stream.Send(&intrigue.LogLine{Error: "remote.unavailable"})
+		}
+		if err := stream.Send(line); err != nil {
+			return err
+		}
+	}
+}
@@ -37,7 +37,20 @@ func main() {
 	// "" = development		-- on a single host (likely localhost)
 	// "C" = containerized	-- in a docker cluster
 
-	procmAddr := config.DefaultSystemProcm.Address
+	// a process manager reads its settings from the project config, remotes are given only
+	// the config of their services
+	procmConf := config.DefaultSystemProcm
+	if !*remoteMode && len(configPaths) != 0 {
+		c, err := config.ParseSystemProcm(configPaths[0])
+		if err != nil {
+			panic(err)
+		}
+		if c != nil {
+			procmConf = c
+		}
+	}
+
+	procmAddr := procmConf.Address
 	env := os.Getenv("ENV")
 	if env == "C" {
 		procmAddr = os.Getenv("PROCM")
@@ -69,7 +82,7 @@ func main() {
 	} else {
 
 		// start a process manager
-		p := NewProcessManager(procmAddr, env, *verbose)
+		p := NewProcessManager(procmAddr, procmConf, env, *verbose)
 		err := p.Start()
 		if err != nil {
 			panic(err)
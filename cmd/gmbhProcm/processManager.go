@@ -30,6 +30,10 @@ type ProcessManager struct {
 	// This is the address that will host the control server
 	Address string
 
+	// bindHost is the host that the control server and the remotes listen on, empty if they
+	// listen on their addresses
+	bindHost string
+
 	// mode from env controls things such as how signals are handled
 	env string
 
@@ -49,7 +53,8 @@ var procm *ProcessManager
 // NewProcessManager instantiates a new pm if one has not already been created. Note that this
 // should be assigned to a global instance to interface with the rpc server. The rpc server should
 // then use the GetProcM function to ensure that the global has not fallen out of scope.
-func NewProcessManager(addr, env string, v bool) *ProcessManager {
+// Remotes are assigned addresses on the advertised host of conf.
+func NewProcessManager(addr string, conf *config.SystemProcm, env string, v bool) *ProcessManager {
 
 	// Make sure that it is never allowed to overrite once already instantiated
 	if procm != nil {
@@ -61,7 +66,8 @@ func NewProcessManager(addr, env string, v bool) *ProcessManager {
 		CodeName:   config.Code,
 		startTime:  time.Now(),
 		Address:    addr,
-		router:     NewRouter(),
+		bindHost:   conf.BindHost,
+		router:     NewRouter(conf.AdvertiseHost, conf.BindHost),
 		env:        env,
 		verbose:    v,
 		mu:         &sync.Mutex{},
@@ -89,6 +95,7 @@ func GetProcM() (*ProcessManager, error) {
 // Start launches the grpc server using the control service in the cabal package
 func (p *ProcessManager) Start() error {
 	p.con = rpc.NewControlConnection(p.Address, &controlServer{})
+	p.con.BindAddress = address.Bind(p.bindHost, p.Address)
 	err := p.con.Connect()
 	if err != nil {
 		return err
@@ -304,15 +311,17 @@ type Router struct {
 	Verbose bool
 }
 
-// NewRouter initializes and returns a new Router struct
-func NewRouter() *Router {
+// NewRouter initializes and returns a new Router struct. Remotes are assigned addresses on
+// host and listen on bind, the same as host when empty.
+func NewRouter(host, bind string) *Router {
 	r := &Router{
 		remotes:   make(map[string]*RemoteServer),
 		idCounter: 100,
-		addr:      address.NewHandler(config.Localhost, config.RemotePort, config.RemotePort+1000),
+		addr:      address.NewHandler(host, config.RemotePort, config.RemotePort+1000),
 		mu:        &sync.Mutex{},
 		Verbose:   true,
 	}
+	r.addr.SetBindHost(bind)
 	return r
 }
 
@@ -385,9 +394,12 @@ func (r *Router) addToMap(rm *RemoteServer) error {
 	return nil
 }
 
-// removeRemote from the map
+// removeRemote from the map and release its address
 func (r *Router) removeRemote(remoteID string) {
 	print("removing " + remoteID)
+	if remote := r.remotes[remoteID]; remote != nil {
+		r.addr.Release(remote.Address)
+	}
 	delete(r.remotes, remoteID)
 }
 
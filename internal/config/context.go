@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+///////////////////////////////////////////////////////////////////////////////////
+// gmbh CLI config
+///////////////////////////////////////////////////////////////////////////////////
+
+// CLIConfig is the user config of the gmbh cli. Each context names a gmbh cluster that the
+// cli can be pointed at; the current context is used when no other is given.
+type CLIConfig struct {
+	CurrentContext string        `toml:"current_context"`
+	Contexts       []*CLIContext `toml:"context"`
+}
+
+// CLIContext is the addresses of the core and procm of one cluster. Config is the path to a
+// project config file to read them from instead, the addresses win if both are set.
+type CLIContext struct {
+	Name   string `toml:"name"`
+	Core   string `toml:"core,omitempty"`
+	Procm  string `toml:"procm,omitempty"`
+	Config string `toml:"config,omitempty"`
+}
+
+// CLIConfigPath returns the path of the user config, $GMBH_CONFIG if it is set and otherwise
+// .gmbh/config.toml in the home directory
+func CLIConfigPath() string {
+	if p := os.Getenv("GMBH_CONFIG"); p != "" {
+		return p
+	}
+	home := os.Getenv("HOME")
+	if home == "" {
+		return filepath.Join(".gmbh", "config.toml")
+	}
+	return filepath.Join(home, ".gmbh", "config.toml")
+}
+
+// ParseCLIConfig returns the user config at path, an empty config if there is no file
+func ParseCLIConfig(path string) (*CLIConfig, error) {
+	c := &CLIConfig{}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return c, nil
+	}
+	if _, err := toml.DecodeFile(path, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Write the config to path, creating its directory if needed
+func (c *CLIConfig) Write(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return toml.NewEncoder(f).Encode(c)
+}
+
+// Lookup returns the context with name
+func (c *CLIConfig) Lookup(name string) (*CLIContext, error) {
+	for _, ctx := range c.Contexts {
+		if ctx.Name == name {
+			return ctx, nil
+		}
+	}
+	return nil, errors.New("context not found: " + name)
+}
+
+// Set adds ctx or replaces the context with the same name
+func (c *CLIConfig) Set(ctx *CLIContext) {
+	for i, existing := range c.Contexts {
+		if existing.Name == ctx.Name {
+			c.Contexts[i] = ctx
+			return
+		}
+	}
+	c.Contexts = append(c.Contexts, ctx)
+}
+
+// Remove the context with name; it is no longer current if it was
+func (c *CLIConfig) Remove(name string) error {
+	for i, ctx := range c.Contexts {
+		if ctx.Name == name {
+			c.Contexts = append(c.Contexts[:i], c.Contexts[i+1:]...)
+			if c.CurrentContext == name {
+				c.CurrentContext = ""
+			}
+			return nil
+		}
+	}
+	return errors.New("context not found: " + name)
+}
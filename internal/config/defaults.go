@@ -51,6 +51,8 @@ var DefaultSystemProcm = &SystemProcm{
 	KeepAlive: duration{time.Second * 45},
 	Verbose:   true,
 	BinPath:   filepath.Join(os.Getenv("$GOPATH"), "bin", "gmbhProcm"),
+
+	AdvertiseHost: Localhost,
 }
 
 // DefaultSystemCore holds default core settings
@@ -61,6 +63,22 @@ var DefaultSystemCore = &SystemCore{
 	Address:   "localhost:49500",
 	KeepAlive: duration{time.Second * 45},
 	BinPath:   filepath.Join(os.Getenv("$GOPATH"), "bin", "gmbhCore"),
+
+	AdvertiseHost:        Localhost,
+	CompressionThreshold: 1024,
+	WebSocketPeerGroups:  []string{"websocket"},
+	DNSDomain:            "gmbh.local",
+	HealthInterval:       duration{time.Second * 10},
+	HealthTimeout:        duration{time.Second * 2},
+	StaleAfter:           duration{time.Second * 30},
+	ReapAfter:            duration{time.Minute * 5},
+}
+
+// DefaultSystemGateway holds default gateway settings. No routes are allowed by default.
+var DefaultSystemGateway = &SystemGateway{
+	Address:     "localhost:8080",
+	Name:        "gateway",
+	MaxBodySize: 10 << 20,
 }
 
 // DefaultSystemConfig is the complete default system config
@@ -86,6 +104,10 @@ var (
 	// ManifestPath is the path from the project directory in which manifest toml files
 	// should be stored
 	ManifestPath = filepath.Join(InternalFiles, "manifest")
+
+	// RegistryPath is the path from the project directory in which core persists the
+	// service registry
+	RegistryPath = filepath.Join(InternalFiles, "registry")
 )
 
 const (
@@ -95,6 +117,9 @@ const (
 	// CoreLogName for log file at Log Path
 	CoreLogName = "coreData.log"
 
+	// AuditLogName for the access control audit file at Log Path
+	AuditLogName = "audit.log"
+
 	// StdoutExt is the extensions for stdout files
 	StdoutExt = "-stdout.log"
 
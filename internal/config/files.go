@@ -12,6 +12,14 @@ RUN go get github.com/golang/protobuf/proto \
     && go get github.com/fatih/color \
     && go get github.com/BurntSushi/toml \
     && go get github.com/rs/xid \
+    && go get github.com/vmihailenco/msgpack \
+    && go get github.com/klauspost/compress \
+    && go get github.com/gorilla/websocket \
+    && go get github.com/nsf/termbox-go \
+    && go get gopkg.in/yaml.v2 \
+    && go get github.com/hashicorp/raft \
+    && go get github.com/hashicorp/raft-boltdb \
+    && go get github.com/miekg/dns \
     && npm i 
 
 
@@ -25,13 +33,15 @@ ARG CACHEBUST=1
 RUN git clone https://github.com/gmbh-micro/gmbh.git \ 
     && cd gmbh \
     && mkdir -p $GOPATH"/src/github.com/gmbh-micro" \ 
-    && cp -a ./internal/* $GOPATH"/src/github.com/gmbh-micro"/ 
+    && cp -a ./internal/* $GOPATH"/src/github.com/gmbh-micro"/ \
+    && cp -a ./pkg/* $GOPATH"/src/github.com/gmbh-micro"/
 
 WORKDIR $SRCDIR/gmbh
 
 RUN go build -v -o ./bin/gmbh ./cmd/gmbh/*.go \
     && go build -v -o ./bin/gmbhCore ./cmd/gmbhCore/*.go \
     && go build -v -o ./bin/gmbhProcm ./cmd/gmbhProcm/*.go \
+    && go build -v -o ./bin/gmbhGateway ./cmd/gmbhGateway/*.go \
     && cp ./bin/gmbh* $GOPATH/bin
 
 WORKDIR /
@@ -48,7 +58,15 @@ RUN go get github.com/golang/protobuf/proto \
     && go get google.golang.org/grpc \
     && go get github.com/fatih/color \
     && go get github.com/BurntSushi/toml \
-    && go get github.com/rs/xid
+    && go get github.com/rs/xid \
+    && go get github.com/vmihailenco/msgpack \
+    && go get github.com/klauspost/compress \
+    && go get github.com/gorilla/websocket \
+    && go get github.com/nsf/termbox-go \
+    && go get gopkg.in/yaml.v2 \
+    && go get github.com/hashicorp/raft \
+    && go get github.com/hashicorp/raft-boltdb \
+    && go get github.com/miekg/dns
 
 
 ENV SRCDIR=/build/gmbh
@@ -69,6 +87,7 @@ WORKDIR $SRCDIR/gmbh
 RUN go build -v -o ./bin/gmbh ./cmd/gmbh/*.go \
     && go build -v -o ./bin/gmbhCore ./cmd/gmbhCore/*.go \
     && go build -v -o ./bin/gmbhProcm ./cmd/gmbhProcm/*.go \
+    && go build -v -o ./bin/gmbhGateway ./cmd/gmbhGateway/*.go \
     && cp ./bin/gmbh* $GOPATH/bin
 
 WORKDIR /
@@ -89,7 +108,9 @@ RUN go get github.com/golang/protobuf/proto \
     && go get google.golang.org/grpc \
     && go get github.com/fatih/color \
     && go get github.com/BurntSushi/toml \
-    && go get github.com/rs/xid
+    && go get github.com/rs/xid \
+    && go get github.com/vmihailenco/msgpack \
+    && go get github.com/klauspost/compress
 
 
 ENV SRCDIR=/build/gmbh
@@ -15,7 +15,10 @@ import (
 type SystemConfig struct {
 	Core        *SystemCore      `toml:"core"`
 	Procm       *SystemProcm     `toml:"procm"`
+	Gateway     *SystemGateway   `toml:"gateway"`
 	Service     []*ServiceConfig `toml:"service"`
+	ACL         []*ACLRule       `toml:"acl"`
+	Limit       []*LimitConfig   `toml:"limit"`
 	Fingerprint string           `toml:"fingerprint"`
 	MaxPerNode  int              `toml:"max_services_per_node"`
 	Dashboard   bool             `toml:"include_dashboard"`
@@ -29,6 +32,65 @@ type SystemCore struct {
 	Address   string   `toml:"address"`
 	KeepAlive duration `toml:"keep_alive"`
 	BinPath   string   `toml:"core_bin"`
+	AuditLog  string   `toml:"audit_log"`
+
+	// AdminToken must be sent by `gmbh svc` to evict, drain or undrain a service, those
+	// requests are refused when it is empty
+	AdminToken string `toml:"admin_token"`
+
+	// AdvertiseHost is the host of the addresses that core assigns to services, the name or
+	// ip that other machines reach them at. BindHost is the host that core and the services
+	// listen on, such as 0.0.0.0, :: or the ip of one interface, empty listens on the
+	// advertised address. Either may be an ipv6 literal.
+	AdvertiseHost string `toml:"advertise_host"`
+	BindHost      string `toml:"bind_host"`
+
+	// Registry is the directory that the service registry is persisted to so that it
+	// survives a restart, "none" keeps it in memory only
+	Registry string `toml:"registry"`
+
+	// Cluster lists every core of a highly available setup, this one included. The
+	// registry is replicated between them and any of them can serve services. Leave it
+	// empty to run a single core.
+	Cluster []*ClusterPeer `toml:"cluster"`
+
+	// Compression is the only algorithm that services may use, empty allows any and
+	// "none" turns compression off. Messages smaller than the threshold in bytes are
+	// never compressed.
+	Compression          string `toml:"compression"`
+	CompressionThreshold int    `toml:"compression_threshold"`
+
+	// WebSocket is the address to serve browser clients on, empty turns it off. Only the
+	// "service/method" patterns in WebSocketRoutes may be called over the socket, and only
+	// on services that share one of WebSocketPeerGroups. Clients that send no Origin
+	// header are refused unless WebSocketAllowNoOrigin is set.
+	WebSocket              string   `toml:"websocket"`
+	WebSocketOrigins       []string `toml:"websocket_origins"`
+	WebSocketRoutes        []string `toml:"websocket_routes"`
+	WebSocketPeerGroups    []string `toml:"websocket_peer_groups"`
+	WebSocketAllowNoOrigin bool     `toml:"websocket_allow_no_origin"`
+
+	// DNS is the address to answer queries for the running services on, empty turns it
+	// off. Services are found under DNSDomain.
+	DNS       string `toml:"dns"`
+	DNSDomain string `toml:"dns_domain"`
+
+	// HealthInterval is how often core polls the health check of each running service, and
+	// HealthTimeout how long it waits for the result
+	HealthInterval duration `toml:"health_interval"`
+	HealthTimeout  duration `toml:"health_timeout"`
+
+	// StaleAfter is how long core waits without hearing from a running service before it
+	// marks it as failed. Failed and shut down services are removed ReapAfter later.
+	StaleAfter duration `toml:"stale_after"`
+	ReapAfter  duration `toml:"reap_after"`
+}
+
+// ClusterPeer is one core of a cluster. Address is where it serves services, the same as
+// the address of a single core, and Raft is where it replicates the registry with the others.
+type ClusterPeer struct {
+	Address string `toml:"address"`
+	Raft    string `toml:"raft"`
 }
 
 // SystemProcm stores gmbhProcm settings
@@ -37,6 +99,22 @@ type SystemProcm struct {
 	KeepAlive duration `toml:"keep_alive"`
 	Verbose   bool     `toml:"verbose"`
 	BinPath   string   `toml:"core_bin"`
+
+	// AdvertiseHost and BindHost are the same as for core, for the addresses that procm
+	// assigns to remotes
+	AdvertiseHost string `toml:"advertise_host"`
+	BindHost      string `toml:"bind_host"`
+}
+
+// SystemGateway stores gmbhGateway settings. Routes is the allow-list of "service/method"
+// patterns that may be called over http, with the same matching as the acl rules.
+type SystemGateway struct {
+	Address     string   `toml:"address"`
+	Name        string   `toml:"name"`
+	PeerGroups  []string `toml:"peer_groups"`
+	Routes      []string `toml:"routes"`
+	CORSOrigins []string `toml:"cors_origins"`
+	MaxBodySize int64    `toml:"max_body_size"`
 }
 
 // ServiceConfig is the static data needed to launch a service from the service launcher
@@ -53,6 +131,26 @@ type ServiceConfig struct {
 	ProjPath    string
 }
 
+// ACLRule allows the service From to call Methods on the service To. From, To and each
+// of the methods may contain wildcards in the form understood by path.Match
+type ACLRule struct {
+	From    string   `toml:"from"`
+	To      string   `toml:"to"`
+	Methods []string `toml:"methods"`
+}
+
+// LimitConfig restricts the requests that core forwards either to Target or from Sender.
+// Rate is the number of requests per second that are allowed with bursts of up to Burst
+// requests. MaxInFlight is the number of requests that may be waiting on a response at
+// the same time. A zero value disables that part of the limit.
+type LimitConfig struct {
+	Target      string  `toml:"target"`
+	Sender      string  `toml:"sender"`
+	Rate        float64 `toml:"rate"`
+	Burst       int     `toml:"burst"`
+	MaxInFlight int     `toml:"max_in_flight"`
+}
+
 // ParseSystemConfig parses the entire system config from the file passed in
 // otherwise returns an error
 func ParseSystemConfig(configFile string) (*SystemConfig, error) {
@@ -82,6 +180,18 @@ func ParseSystemProcm(configFile string) (*SystemProcm, error) {
 	return system.Procm, nil
 }
 
+// ParseSystemGateway returns only the gateway settings
+func ParseSystemGateway(configFile string) (*SystemGateway, error) {
+	system, err := ParseSystemConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if system.Gateway == nil {
+		return DefaultSystemGateway, nil
+	}
+	return system.Gateway, nil
+}
+
 // ParseServices returns only the services and the fingerprint
 func ParseServices(configFile string) ([]*ServiceConfig, string, error) {
 	system, err := ParseSystemConfig(configFile)
@@ -91,6 +201,24 @@ func ParseServices(configFile string) ([]*ServiceConfig, string, error) {
 	return system.Service, system.Fingerprint, nil
 }
 
+// ParseACL returns only the access control rules
+func ParseACL(configFile string) ([]*ACLRule, error) {
+	system, err := ParseSystemConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return system.ACL, nil
+}
+
+// ParseLimits returns only the rate and concurrency limits
+func ParseLimits(configFile string) ([]*LimitConfig, error) {
+	system, err := ParseSystemConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return system.Limit, nil
+}
+
 // Verify that a service config is balid
 func (s *ServiceConfig) Verify() error {
 	if s.BinPath == "" && (s.Language == "" || s.SrcPath == "") {
@@ -111,6 +239,30 @@ func setDefaults(c *SystemConfig) {
 		if c.Core.BinPath == "" {
 			c.Core.BinPath = DefaultSystemCore.BinPath
 		}
+		if c.Core.AdvertiseHost == "" {
+			c.Core.AdvertiseHost = DefaultSystemCore.AdvertiseHost
+		}
+		if c.Core.CompressionThreshold == 0 {
+			c.Core.CompressionThreshold = DefaultSystemCore.CompressionThreshold
+		}
+		if len(c.Core.WebSocketPeerGroups) == 0 {
+			c.Core.WebSocketPeerGroups = DefaultSystemCore.WebSocketPeerGroups
+		}
+		if c.Core.DNSDomain == "" {
+			c.Core.DNSDomain = DefaultSystemCore.DNSDomain
+		}
+		if c.Core.HealthInterval.Duration == 0 {
+			c.Core.HealthInterval = DefaultSystemCore.HealthInterval
+		}
+		if c.Core.HealthTimeout.Duration == 0 {
+			c.Core.HealthTimeout = DefaultSystemCore.HealthTimeout
+		}
+		if c.Core.StaleAfter.Duration == 0 {
+			c.Core.StaleAfter = DefaultSystemCore.StaleAfter
+		}
+		if c.Core.ReapAfter.Duration == 0 {
+			c.Core.ReapAfter = DefaultSystemCore.ReapAfter
+		}
 	}
 	if c.Procm != nil {
 		if c.Procm.Address == "" {
@@ -119,6 +271,20 @@ func setDefaults(c *SystemConfig) {
 		if c.Procm.BinPath == "" {
 			c.Procm.BinPath = DefaultSystemProcm.BinPath
 		}
+		if c.Procm.AdvertiseHost == "" {
+			c.Procm.AdvertiseHost = DefaultSystemProcm.AdvertiseHost
+		}
+	}
+	if c.Gateway != nil {
+		if c.Gateway.Address == "" {
+			c.Gateway.Address = DefaultSystemGateway.Address
+		}
+		if c.Gateway.Name == "" {
+			c.Gateway.Name = DefaultSystemGateway.Name
+		}
+		if c.Gateway.MaxBodySize == 0 {
+			c.Gateway.MaxBodySize = DefaultSystemGateway.MaxBodySize
+		}
 	}
 	if c.MaxPerNode == 0 {
 		c.MaxPerNode = DefaultSystemConfig.MaxPerNode
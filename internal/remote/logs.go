@@ -0,0 +1,182 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gmbh-micro/config"
+	"github.com/gmbh-micro/rpc/intrigue"
+)
+
+// logPoll is how often a followed log is checked for new lines
+const logPoll = time.Millisecond * 250
+
+func (s *remoteServer) Logs(in *intrigue.LogRequest, stream intrigue.Remote_LogsServer) error {
+
+	print("-> logs; %s", in.String())
+
+	path := r.logPath
+	if in.GetTarget() != "" {
+		service, err := r.LookupService(in.GetTarget())
+		if err != nil {
+			return stream.Send(&intrigue.LogLine{Error: "service.notFound"})
+		}
+		path = service.LogPath
+	}
+	if path == "" {
+		return stream.Send(&intrigue.LogLine{Error: "log.notFound"})
+	}
+
+	filter, err := newLogFilter(in)
+	if err != nil {
+		return stream.Send(&intrigue.LogLine{Error: "log.invalidFilter=" + err.Error()})
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return stream.Send(&intrigue.LogLine{Error: "log.unreadable=" + err.Error()})
+	}
+	defer f.Close()
+
+	return readLog(stream.Context(), f, int(in.GetTail()), in.GetFollow(), filter, func(line string) error {
+		return stream.Send(&intrigue.LogLine{Line: line})
+	})
+}
+
+// readLog sends the lines of f that pass filter, or only the last tail of them. When follow is
+// set it keeps sending lines as they are written until ctx is done.
+func readLog(ctx context.Context, f *os.File, tail int, follow bool, filter *logFilter, send func(string) error) error {
+
+	reader := bufio.NewReader(f)
+	var offset int64
+	partial := ""
+
+	// last holds the last tail lines until the end of the log is first reached
+	last := []string{}
+	caughtUp := false
+
+	for {
+		chunk, err := reader.ReadString('\n')
+		offset += int64(len(chunk))
+		partial += chunk
+
+		if err == nil {
+			line := strings.TrimRight(partial, "\r\n")
+			partial = ""
+			if !filter.match(line) {
+				continue
+			}
+			if !caughtUp && tail > 0 {
+				last = append(last, line)
+				if len(last) > tail {
+					last = last[1:]
+				}
+				continue
+			}
+			if err := send(line); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+
+		// without follow the last line will not be finished so it is sent as it is
+		if !follow && partial != "" && filter.match(partial) {
+			last = append(last, partial)
+			if tail > 0 && len(last) > tail {
+				last = last[1:]
+			}
+		}
+
+		if !caughtUp {
+			caughtUp = true
+			for _, line := range last {
+				if err := send(line); err != nil {
+					return err
+				}
+			}
+			last = nil
+		}
+
+		if !follow {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(logPoll):
+		}
+
+		// start over if the log has been truncated
+		if info, err := f.Stat(); err == nil && info.Size() < offset {
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			reader.Reset(f)
+			offset = 0
+			partial = ""
+		}
+	}
+}
+
+// logFilter skips the lines that do not match the grep pattern or were written before since.
+// Only lines that start with a gmbh log stamp have a time; the lines that follow take on the
+// time of the last stamped line.
+type logFilter struct {
+	grep  *regexp.Regexp
+	since time.Time
+	last  time.Time
+}
+
+func newLogFilter(in *intrigue.LogRequest) (*logFilter, error) {
+	filter := &logFilter{}
+	if in.GetGrep() != "" {
+		re, err := regexp.Compile(in.GetGrep())
+		if err != nil {
+			return nil, err
+		}
+		filter.grep = re
+	}
+	if in.GetSince() != "" {
+		since, err := time.Parse(time.RFC3339, in.GetSince())
+		if err != nil {
+			return nil, err
+		}
+		// stamps only go down to the minute
+		filter.since = since.Truncate(time.Minute)
+	}
+	return filter, nil
+}
+
+func (f *logFilter) match(line string) bool {
+	if !f.since.IsZero() {
+		if stamp, ok := logStamp(line); ok {
+			f.last = stamp
+		}
+		if f.last.Before(f.since) {
+			return false
+		}
+	}
+	return f.grep == nil || f.grep.MatchString(line)
+}
+
+// logStamp parses the "[06/01/02 15:04]" stamp that gmbh starts its log lines with
+func logStamp(line string) (time.Time, bool) {
+	n := len(config.LogStamp)
+	if len(line) < n+2 || line[0] != '[' || line[n+1] != ']' {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(config.LogStamp, line[1:n+1], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
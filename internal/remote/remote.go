@@ -89,6 +89,9 @@ type registration struct {
 
 	// an id given from core to send ping requests with
 	fingerprint string
+
+	// bindHost is the host to listen on at the port of address, empty to listen on address
+	bindHost string
 }
 
 /**********************************************************************************
@@ -209,6 +212,7 @@ func (r *Remote) connect() {
 	r.reg = reg
 	r.id = reg.id
 	r.con = rpc.NewRemoteConnection(reg.address, &remoteServer{})
+	r.con.BindAddress = address.Bind(reg.bindHost, reg.address)
 	r.mu.Unlock()
 
 	err := r.con.Connect()
@@ -290,6 +294,7 @@ func (r *Remote) makeCoreConnectRequest() (*registration, error) {
 		id:          reply.GetServiceInfo().GetID(),
 		address:     reply.GetServiceInfo().GetAddress(),
 		fingerprint: reply.GetServiceInfo().GetFingerprint(),
+		bindHost:    reply.GetServiceInfo().GetBindHost(),
 	}
 
 	if r.env == "C" {
@@ -452,6 +457,18 @@ func (s *remoteServer) NotifyAction(ctx context.Context, in *intrigue.Action) (*
 	} else if request == "service.restart.all" {
 		go r.RestartAll()
 		return &intrigue.Action{Message: "success"}, nil
+	} else if request == "service.kill.one" {
+
+		target, err := r.LookupService(TargetID)
+		if err != nil {
+			return &intrigue.Action{Error: "service.notFound"}, nil
+		}
+		if target.Mode != service.Managed {
+			return &intrigue.Action{Error: "service.notManaged"}, nil
+		}
+
+		target.Kill()
+		return &intrigue.Action{Message: "killed"}, nil
 	}
 
 	return &intrigue.Action{Error: "request.unknown"}, nil
@@ -2,49 +2,220 @@ package address
 
 import (
 	"fmt"
+	"net"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
+// unixPrefix is the scheme of unix socket addresses, the same as rpc.UnixPrefix
+const unixPrefix = "unix://"
+
 // Handler ; as in address handler. Manages the assignemnt of addresses
 type Handler struct {
-	host        string
-	portHigh    int
-	portLow     int
+	// host is the host of the addresses that are handed out, the one others reach them at
+	host string
+
+	// bind is the host that the addresses are listened on, empty if it is the same as host
+	bind string
+
+	// socketDir is set when host is a unix:// directory, the addresses are then socket files
+	// in it named after the port that would otherwise have been assigned, such as 49504.sock
+	socketDir string
+
+	portHigh int
+	portLow  int
+
+	// currentPort is the last port assigned, the search for the next one starts after it so
+	// that a port that was just released is not handed out again straight away
 	currentPort int
-	table       map[string]string
-	usedPorts   map[int]bool
-	mu          *sync.Mutex
+
+	// usedPorts are the ports that have been assigned or reserved and not released
+	usedPorts map[int]bool
+	mu        *sync.Mutex
 }
 
-// NewHandler returns a new address handler
+// NewHandler returns a new address handler. When host is a directory such as unix:///tmp/gmbh
+// the handler assigns unix sockets in it instead of ports.
 func NewHandler(host string, portLow, portHigh int) *Handler {
-	return &Handler{
-		host:        host,
+	h := &Handler{
+		host:        trimHost(host),
 		portLow:     portLow,
 		currentPort: portLow,
 		portHigh:    portHigh,
-		table:       make(map[string]string),
 		usedPorts:   make(map[int]bool),
 		mu:          &sync.Mutex{},
 	}
+	if strings.HasPrefix(host, unixPrefix) {
+		h.socketDir = filepath.Clean(strings.TrimPrefix(host, unixPrefix))
+	}
+	return h
+}
+
+// SetBindHost sets the host that the assigned addresses are listened on when it differs from
+// the host they are reached at, such as 0.0.0.0 or the ip of one interface. It is used to
+// check that a port is free.
+func (h *Handler) SetBindHost(bind string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.bind = trimHost(bind)
 }
 
-// NextAddress assignes the next address of the handler
+// NextAddress assignes the next address of the handler that is neither in use nor taken by
+// another process on the host
 func (h *Handler) NextAddress() (string, error) {
 	next, err := h.nextPort()
 	if err != nil {
 		return "", err
 	}
-	return h.host + ":" + strconv.Itoa(next), nil
+	return h.address(next), nil
 }
 
-// nextPort returns the next port number and increments the current port
+// nextPort returns the next free port and marks it as used. Ports are handed out two apart,
+// wrapping around to the bottom of the range once the top is reached. Sockets are not limited
+// by the range and carry on above it once it is full.
 func (h *Handler) nextPort() (int, error) {
-	if h.currentPort+2 < h.portHigh {
-		port := h.currentPort + 2
-		h.currentPort += 2
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	port := h.currentPort
+	for i := 0; i < (h.portHigh-h.portLow)/2; i++ {
+		port += 2
+		if port >= h.portHigh {
+			port = h.portLow + 2
+		}
+		if h.usedPorts[port] || !h.free(port) {
+			continue
+		}
+		h.usedPorts[port] = true
+		h.currentPort = port
 		return port, nil
 	}
+	if h.socketDir != "" {
+		for port = h.portHigh; ; port += 2 {
+			if !h.usedPorts[port] && h.free(port) {
+				h.usedPorts[port] = true
+				return port, nil
+			}
+		}
+	}
 	return -1, fmt.Errorf("out of port range")
 }
+
+// Claim assigns the address with port, which need not be in the range of the handler, if it
+// is neither in use nor taken by another process on the host
+func (h *Handler) Claim(port int) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if port <= 0 || (h.socketDir == "" && port > 65535) {
+		return "", fmt.Errorf("invalid port")
+	}
+	if h.usedPorts[port] || !h.free(port) {
+		return "", fmt.Errorf("port unavailable")
+	}
+	h.usedPorts[port] = true
+	return h.address(port), nil
+}
+
+// Reserve marks an address given out before a restart as used, so that it is not assigned
+// again. Addresses on other hosts are ignored.
+func (h *Handler) Reserve(address string) {
+	port, ok := h.Port(address)
+	if !ok {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.usedPorts[port] = true
+}
+
+// Release frees an address that was assigned by the handler or reserved, so that it may be
+// handed out again
+func (h *Handler) Release(address string) {
+	port, ok := h.Port(address)
+	if !ok {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.usedPorts, port)
+}
+
+// Port returns the port of address if it is on the host of the handler, for a socket in the
+// directory of the handler it is the number in its name
+func (h *Handler) Port(address string) (int, bool) {
+	if h.socketDir != "" {
+		path := strings.TrimPrefix(address, unixPrefix)
+		if !strings.HasPrefix(address, unixPrefix) || filepath.Dir(path) != h.socketDir {
+			return 0, false
+		}
+		port, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(path), ".sock"))
+		if err != nil {
+			return 0, false
+		}
+		return port, true
+	}
+	host, p, err := net.SplitHostPort(address)
+	if err != nil || host != h.host {
+		return 0, false
+	}
+	port, err := strconv.Atoi(p)
+	if err != nil {
+		return 0, false
+	}
+	return port, true
+}
+
+func (h *Handler) address(port int) string {
+	if h.socketDir != "" {
+		return unixPrefix + filepath.Join(h.socketDir, strconv.Itoa(port)+".sock")
+	}
+	return net.JoinHostPort(h.host, strconv.Itoa(port))
+}
+
+// free checks that nothing on the host is listening on port. A socket file that nothing
+// answers on is left behind by a server that did not shut down and is removed when the
+// address is next listened on.
+func (h *Handler) free(port int) bool {
+	if h.socketDir != "" {
+		con, err := net.DialTimeout("unix", strings.TrimPrefix(h.address(port), unixPrefix), time.Second)
+		if err != nil {
+			return true
+		}
+		con.Close()
+		return false
+	}
+	addr := h.address(port)
+	if h.bind != "" {
+		addr = net.JoinHostPort(h.bind, strconv.Itoa(port))
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return false
+	}
+	l.Close()
+	return true
+}
+
+// Bind returns the address to listen on for address when listening on host, empty host
+// listens on address itself. Only the port of address is kept, so a service reached at
+// example.com:49504 with host 0.0.0.0 listens on 0.0.0.0:49504. Sockets are always listened on
+// at their own path.
+func Bind(host, address string) string {
+	if host == "" || strings.HasPrefix(address, unixPrefix) {
+		return address
+	}
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return net.JoinHostPort(trimHost(host), port)
+}
+
+// trimHost removes the brackets around an ipv6 literal such as [::1], they are added back
+// where the host is joined with a port
+func trimHost(host string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+}
@@ -0,0 +1,229 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/stats"
+
+	// registers the gzip compressor with grpc
+	_ "google.golang.org/grpc/encoding/gzip"
+)
+
+const (
+	// Gzip is the name of the gzip compressor
+	Gzip = "gzip"
+
+	// Zstd is the name of the zstd compressor
+	Zstd = "zstd"
+
+	// NoCompression turns compression off where an algorithm is asked for
+	NoCompression = "none"
+)
+
+func init() {
+	encoding.RegisterCompressor(&zstdCompressor{
+		encoders: &sync.Pool{},
+		decoders: &sync.Pool{},
+	})
+}
+
+// NegotiateCompression returns the compression algorithm to use between two parties where
+// one has asked for wanted and the other only allows allowed. An empty allowed means that
+// any registered algorithm is fine. The empty string is returned if nothing can be agreed on,
+// in which case messages should be sent uncompressed.
+func NegotiateCompression(wanted, allowed string) string {
+	if wanted == "" || wanted == NoCompression || allowed == NoCompression {
+		return ""
+	}
+	if allowed != "" && allowed != wanted {
+		return ""
+	}
+	if encoding.GetCompressor(wanted) == nil {
+		return ""
+	}
+	return wanted
+}
+
+// CompressionOption returns the call options to compress msg with algorithm if it is at
+// least threshold bytes. The response is compressed with the same algorithm by the server.
+func CompressionOption(algorithm string, threshold int, msg proto.Message) []grpc.CallOption {
+	if algorithm == "" || proto.Size(msg) < threshold {
+		return nil
+	}
+	return []grpc.CallOption{grpc.UseCompressor(algorithm)}
+}
+
+// zstdCompressor implements the grpc encoding.Compressor with pools of encoders and decoders
+// as they are expensive to create
+type zstdCompressor struct {
+	encoders *sync.Pool
+	decoders *sync.Pool
+}
+
+func (z *zstdCompressor) Name() string {
+	return Zstd
+}
+
+func (z *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if zw, ok := z.encoders.Get().(*zstdWriter); ok {
+		zw.Reset(w)
+		return zw, nil
+	}
+	enc, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return &zstdWriter{Encoder: enc, pool: z.encoders}, nil
+}
+
+func (z *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	if zr, ok := z.decoders.Get().(*zstdReader); ok {
+		if err := zr.Reset(r); err != nil {
+			z.decoders.Put(zr)
+			return nil, err
+		}
+		return zr, nil
+	}
+	dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return &zstdReader{Decoder: dec, pool: z.decoders}, nil
+}
+
+// zstdWriter returns its encoder to the pool when closed
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+func (w *zstdWriter) Close() error {
+	err := w.Encoder.Close()
+	w.pool.Put(w)
+	return err
+}
+
+// zstdReader returns its decoder to the pool once the message has been read, or closes it if
+// the message could not be read
+type zstdReader struct {
+	*zstd.Decoder
+	pool *sync.Pool
+}
+
+func (r *zstdReader) Read(p []byte) (int, error) {
+	n, err := r.Decoder.Read(p)
+	switch {
+	case err == io.EOF:
+		r.pool.Put(r)
+	case err != nil:
+		r.Decoder.Close()
+	}
+	return n, err
+}
+
+// CompressionStats is a grpc stats handler that counts the size of compressed messages before
+// and after compression for each algorithm
+type CompressionStats struct {
+	// raw and wire map [algorithm]bytes
+	raw      map[string]int64
+	wire     map[string]int64
+	messages map[string]int64
+	mu       *sync.Mutex
+}
+
+// NewCompressionStats returns an empty stats handler
+func NewCompressionStats() *CompressionStats {
+	return &CompressionStats{
+		raw:      make(map[string]int64),
+		wire:     make(map[string]int64),
+		messages: make(map[string]int64),
+		mu:       &sync.Mutex{},
+	}
+}
+
+type compressionKey struct{}
+
+// rpcCompression holds the algorithms of a single rpc as learned from its headers
+type rpcCompression struct {
+	in  string
+	out string
+}
+
+// algorithm returns the algorithm of the rpc. Only one of the headers may carry it, but the
+// response is always compressed the same way as the request.
+func (rc *rpcCompression) algorithm() string {
+	if rc.in != "" {
+		return rc.in
+	}
+	return rc.out
+}
+
+// TagRPC implements stats.Handler
+func (s *CompressionStats) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, compressionKey{}, &rpcCompression{})
+}
+
+// HandleRPC implements stats.Handler
+func (s *CompressionStats) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	rc, ok := ctx.Value(compressionKey{}).(*rpcCompression)
+	if !ok {
+		return
+	}
+	switch st := rs.(type) {
+	case *stats.InHeader:
+		rc.in = st.Compression
+	case *stats.OutHeader:
+		rc.out = st.Compression
+	case *stats.InPayload:
+		s.add(rc.algorithm(), st.Length, st.WireLength)
+	case *stats.OutPayload:
+		// the wire length of outgoing messages includes the 5 byte message header
+		s.add(rc.algorithm(), st.Length, st.WireLength-5)
+	}
+}
+
+// TagConn implements stats.Handler
+func (s *CompressionStats) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn implements stats.Handler
+func (s *CompressionStats) HandleConn(ctx context.Context, cs stats.ConnStats) {}
+
+func (s *CompressionStats) add(algorithm string, raw, wire int) {
+	if algorithm == "" || algorithm == encoding.Identity || wire <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.raw[algorithm] += int64(raw)
+	s.wire[algorithm] += int64(wire)
+	s.messages[algorithm]++
+}
+
+// Snapshot returns the counters for each algorithm prefixed with compression.<algorithm>.
+// The raw and wire counters are the bytes before and after compression, saved is the
+// difference and ratio is raw / wire as a percentage, ie 250 for 2.5:1.
+func (s *CompressionStats) Snapshot() map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := make(map[string]int64)
+	for algo, raw := range s.raw {
+		prefix := "compression." + algo + "."
+		wire := s.wire[algo]
+		ret[prefix+"messages"] = s.messages[algo]
+		ret[prefix+"raw"] = raw
+		ret[prefix+"wire"] = wire
+		ret[prefix+"saved"] = raw - wire
+		if wire > 0 {
+			ret[prefix+"ratio"] = raw * 100 / wire
+		}
+	}
+	return ret
+}
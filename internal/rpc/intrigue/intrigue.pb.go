@@ -235,6 +235,63 @@ func (m *DataResponse) GetError() string {
 	return ""
 }
 
+// StreamChunk is one piece of a streaming data request. The first chunk sent by the caller
+// carries the transport, the Error of the last chunk sent back is set if the stream failed.
+type StreamChunk struct {
+	Tport                *Transport `protobuf:"bytes,1,opt,name=Tport,proto3" json:"Tport,omitempty"`
+	Data                 []byte     `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
+	Error                string     `protobuf:"bytes,3,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *StreamChunk) Reset()         { *m = StreamChunk{} }
+func (m *StreamChunk) String() string { return proto.CompactTextString(m) }
+func (*StreamChunk) ProtoMessage()    {}
+func (*StreamChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22f1d98c525e71fb, []int{4}
+}
+
+func (m *StreamChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamChunk.Unmarshal(m, b)
+}
+func (m *StreamChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamChunk.Marshal(b, m, deterministic)
+}
+func (m *StreamChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamChunk.Merge(m, src)
+}
+func (m *StreamChunk) XXX_Size() int {
+	return xxx_messageInfo_StreamChunk.Size(m)
+}
+func (m *StreamChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamChunk proto.InternalMessageInfo
+
+func (m *StreamChunk) GetTport() *Transport {
+	if m != nil {
+		return m.Tport
+	}
+	return nil
+}
+
+func (m *StreamChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *StreamChunk) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type WhoIsRequest struct {
 	Sender               string   `protobuf:"bytes,1,opt,name=Sender,proto3" json:"Sender,omitempty"`
 	Target               string   `protobuf:"bytes,2,opt,name=Target,proto3" json:"Target,omitempty"`
@@ -247,7 +304,7 @@ func (m *WhoIsRequest) Reset()         { *m = WhoIsRequest{} }
 func (m *WhoIsRequest) String() string { return proto.CompactTextString(m) }
 func (*WhoIsRequest) ProtoMessage()    {}
 func (*WhoIsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{4}
+	return fileDescriptor_22f1d98c525e71fb, []int{5}
 }
 
 func (m *WhoIsRequest) XXX_Unmarshal(b []byte) error {
@@ -284,6 +341,7 @@ func (m *WhoIsRequest) GetTarget() string {
 
 type WhoIsResponse struct {
 	TargetAddress        string   `protobuf:"bytes,1,opt,name=TargetAddress,proto3" json:"TargetAddress,omitempty"`
+	Token                string   `protobuf:"bytes,2,opt,name=Token,proto3" json:"Token,omitempty"`
 	Error                string   `protobuf:"bytes,3,opt,name=Error,proto3" json:"Error,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -294,7 +352,7 @@ func (m *WhoIsResponse) Reset()         { *m = WhoIsResponse{} }
 func (m *WhoIsResponse) String() string { return proto.CompactTextString(m) }
 func (*WhoIsResponse) ProtoMessage()    {}
 func (*WhoIsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{5}
+	return fileDescriptor_22f1d98c525e71fb, []int{6}
 }
 
 func (m *WhoIsResponse) XXX_Unmarshal(b []byte) error {
@@ -322,6 +380,13 @@ func (m *WhoIsResponse) GetTargetAddress() string {
 	return ""
 }
 
+func (m *WhoIsResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
 func (m *WhoIsResponse) GetError() string {
 	if m != nil {
 		return m.Error
@@ -339,7 +404,7 @@ func (m *EmptyRequest) Reset()         { *m = EmptyRequest{} }
 func (m *EmptyRequest) String() string { return proto.CompactTextString(m) }
 func (*EmptyRequest) ProtoMessage()    {}
 func (*EmptyRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{6}
+	return fileDescriptor_22f1d98c525e71fb, []int{7}
 }
 
 func (m *EmptyRequest) XXX_Unmarshal(b []byte) error {
@@ -375,7 +440,7 @@ func (m *ServiceUpdate) Reset()         { *m = ServiceUpdate{} }
 func (m *ServiceUpdate) String() string { return proto.CompactTextString(m) }
 func (*ServiceUpdate) ProtoMessage()    {}
 func (*ServiceUpdate) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{7}
+	return fileDescriptor_22f1d98c525e71fb, []int{8}
 }
 
 func (m *ServiceUpdate) XXX_Unmarshal(b []byte) error {
@@ -446,7 +511,7 @@ func (m *Action) Reset()         { *m = Action{} }
 func (m *Action) String() string { return proto.CompactTextString(m) }
 func (*Action) ProtoMessage()    {}
 func (*Action) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{8}
+	return fileDescriptor_22f1d98c525e71fb, []int{9}
 }
 
 func (m *Action) XXX_Unmarshal(b []byte) error {
@@ -515,7 +580,7 @@ func (m *SummaryReceipt) Reset()         { *m = SummaryReceipt{} }
 func (m *SummaryReceipt) String() string { return proto.CompactTextString(m) }
 func (*SummaryReceipt) ProtoMessage()    {}
 func (*SummaryReceipt) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{9}
+	return fileDescriptor_22f1d98c525e71fb, []int{10}
 }
 
 func (m *SummaryReceipt) XXX_Unmarshal(b []byte) error {
@@ -557,6 +622,183 @@ func (m *SummaryReceipt) GetError() string {
 	return ""
 }
 
+type InspectReceipt struct {
+	Service              *ServiceDetail `protobuf:"bytes,1,opt,name=Service,proto3" json:"Service,omitempty"`
+	Error                string         `protobuf:"bytes,3,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *InspectReceipt) Reset()         { *m = InspectReceipt{} }
+func (m *InspectReceipt) String() string { return proto.CompactTextString(m) }
+func (*InspectReceipt) ProtoMessage()    {}
+func (*InspectReceipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22f1d98c525e71fb, []int{11}
+}
+
+func (m *InspectReceipt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InspectReceipt.Unmarshal(m, b)
+}
+func (m *InspectReceipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InspectReceipt.Marshal(b, m, deterministic)
+}
+func (m *InspectReceipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InspectReceipt.Merge(m, src)
+}
+func (m *InspectReceipt) XXX_Size() int {
+	return xxx_messageInfo_InspectReceipt.Size(m)
+}
+func (m *InspectReceipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_InspectReceipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InspectReceipt proto.InternalMessageInfo
+
+func (m *InspectReceipt) GetService() *ServiceDetail {
+	if m != nil {
+		return m.Service
+	}
+	return nil
+}
+
+func (m *InspectReceipt) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type LogRequest struct {
+	RemoteID string `protobuf:"bytes,1,opt,name=RemoteID,proto3" json:"RemoteID,omitempty"`
+	// Target is the id of the service on the remote, empty for the log of the remote itself
+	Target string `protobuf:"bytes,2,opt,name=Target,proto3" json:"Target,omitempty"`
+	Follow bool   `protobuf:"varint,3,opt,name=Follow,proto3" json:"Follow,omitempty"`
+	// Since is an RFC3339 time, lines stamped before it are skipped
+	Since string `protobuf:"bytes,4,opt,name=Since,proto3" json:"Since,omitempty"`
+	// Grep is a regular expression that lines must match
+	Grep string `protobuf:"bytes,5,opt,name=Grep,proto3" json:"Grep,omitempty"`
+	// Tail is the number of lines to send from the end of the log, all of them if 0
+	Tail                 int32    `protobuf:"varint,6,opt,name=Tail,proto3" json:"Tail,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogRequest) Reset()         { *m = LogRequest{} }
+func (m *LogRequest) String() string { return proto.CompactTextString(m) }
+func (*LogRequest) ProtoMessage()    {}
+func (*LogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22f1d98c525e71fb, []int{12}
+}
+
+func (m *LogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogRequest.Unmarshal(m, b)
+}
+func (m *LogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogRequest.Marshal(b, m, deterministic)
+}
+func (m *LogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogRequest.Merge(m, src)
+}
+func (m *LogRequest) XXX_Size() int {
+	return xxx_messageInfo_LogRequest.Size(m)
+}
+func (m *LogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogRequest proto.InternalMessageInfo
+
+func (m *LogRequest) GetRemoteID() string {
+	if m != nil {
+		return m.RemoteID
+	}
+	return ""
+}
+
+func (m *LogRequest) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *LogRequest) GetFollow() bool {
+	if m != nil {
+		return m.Follow
+	}
+	return false
+}
+
+func (m *LogRequest) GetSince() string {
+	if m != nil {
+		return m.Since
+	}
+	return ""
+}
+
+func (m *LogRequest) GetGrep() string {
+	if m != nil {
+		return m.Grep
+	}
+	return ""
+}
+
+func (m *LogRequest) GetTail() int32 {
+	if m != nil {
+		return m.Tail
+	}
+	return 0
+}
+
+type LogLine struct {
+	Line                 string   `protobuf:"bytes,1,opt,name=Line,proto3" json:"Line,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogLine) Reset()         { *m = LogLine{} }
+func (m *LogLine) String() string { return proto.CompactTextString(m) }
+func (*LogLine) ProtoMessage()    {}
+func (*LogLine) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22f1d98c525e71fb, []int{13}
+}
+
+func (m *LogLine) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogLine.Unmarshal(m, b)
+}
+func (m *LogLine) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogLine.Marshal(b, m, deterministic)
+}
+func (m *LogLine) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogLine.Merge(m, src)
+}
+func (m *LogLine) XXX_Size() int {
+	return xxx_messageInfo_LogLine.Size(m)
+}
+func (m *LogLine) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogLine.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogLine proto.InternalMessageInfo
+
+func (m *LogLine) GetLine() string {
+	if m != nil {
+		return m.Line
+	}
+	return ""
+}
+
+func (m *LogLine) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type Ping struct {
 	Status               string   `protobuf:"bytes,1,opt,name=Status,proto3" json:"Status,omitempty"`
 	Time                 string   `protobuf:"bytes,2,opt,name=Time,proto3" json:"Time,omitempty"`
@@ -570,7 +812,7 @@ func (m *Ping) Reset()         { *m = Ping{} }
 func (m *Ping) String() string { return proto.CompactTextString(m) }
 func (*Ping) ProtoMessage()    {}
 func (*Ping) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{10}
+	return fileDescriptor_22f1d98c525e71fb, []int{14}
 }
 
 func (m *Ping) XXX_Unmarshal(b []byte) error {
@@ -616,6 +858,7 @@ type Pong struct {
 	Status               string   `protobuf:"bytes,1,opt,name=Status,proto3" json:"Status,omitempty"`
 	Time                 string   `protobuf:"bytes,2,opt,name=Time,proto3" json:"Time,omitempty"`
 	Error                string   `protobuf:"bytes,3,opt,name=Error,proto3" json:"Error,omitempty"`
+	Health               *Health  `protobuf:"bytes,4,opt,name=Health,proto3" json:"Health,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -625,7 +868,7 @@ func (m *Pong) Reset()         { *m = Pong{} }
 func (m *Pong) String() string { return proto.CompactTextString(m) }
 func (*Pong) ProtoMessage()    {}
 func (*Pong) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{11}
+	return fileDescriptor_22f1d98c525e71fb, []int{15}
 }
 
 func (m *Pong) XXX_Unmarshal(b []byte) error {
@@ -667,6 +910,86 @@ func (m *Pong) GetError() string {
 	return ""
 }
 
+func (m *Pong) GetHealth() *Health {
+	if m != nil {
+		return m.Health
+	}
+	return nil
+}
+
+// Health is the result of the health check of a service. A service that is not
+// live is broken, one that is not ready is up but should not be sent requests.
+type Health struct {
+	Live                 bool              `protobuf:"varint,1,opt,name=Live,proto3" json:"Live,omitempty"`
+	Ready                bool              `protobuf:"varint,2,opt,name=Ready,proto3" json:"Ready,omitempty"`
+	Message              string            `protobuf:"bytes,3,opt,name=Message,proto3" json:"Message,omitempty"`
+	Details              map[string]string `protobuf:"bytes,4,rep,name=Details,proto3" json:"Details,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Checked              string            `protobuf:"bytes,5,opt,name=Checked,proto3" json:"Checked,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Health) Reset()         { *m = Health{} }
+func (m *Health) String() string { return proto.CompactTextString(m) }
+func (*Health) ProtoMessage()    {}
+func (*Health) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22f1d98c525e71fb, []int{16}
+}
+
+func (m *Health) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Health.Unmarshal(m, b)
+}
+func (m *Health) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Health.Marshal(b, m, deterministic)
+}
+func (m *Health) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Health.Merge(m, src)
+}
+func (m *Health) XXX_Size() int {
+	return xxx_messageInfo_Health.Size(m)
+}
+func (m *Health) XXX_DiscardUnknown() {
+	xxx_messageInfo_Health.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Health proto.InternalMessageInfo
+
+func (m *Health) GetLive() bool {
+	if m != nil {
+		return m.Live
+	}
+	return false
+}
+
+func (m *Health) GetReady() bool {
+	if m != nil {
+		return m.Ready
+	}
+	return false
+}
+
+func (m *Health) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Health) GetDetails() map[string]string {
+	if m != nil {
+		return m.Details
+	}
+	return nil
+}
+
+func (m *Health) GetChecked() string {
+	if m != nil {
+		return m.Checked
+	}
+	return ""
+}
+
 type ProcessManager struct {
 	ID                   string     `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
 	Name                 string     `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
@@ -685,7 +1008,7 @@ func (m *ProcessManager) Reset()         { *m = ProcessManager{} }
 func (m *ProcessManager) String() string { return proto.CompactTextString(m) }
 func (*ProcessManager) ProtoMessage()    {}
 func (*ProcessManager) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{12}
+	return fileDescriptor_22f1d98c525e71fb, []int{17}
 }
 
 func (m *ProcessManager) XXX_Unmarshal(b []byte) error {
@@ -768,7 +1091,14 @@ type NewService struct {
 	IsServer bool     `protobuf:"varint,3,opt,name=IsServer,proto3" json:"IsServer,omitempty"`
 	IsClient bool     `protobuf:"varint,4,opt,name=IsClient,proto3" json:"IsClient,omitempty"`
 	// string PeerGroup = 5;
-	PeerGroups           []string `protobuf:"bytes,6,rep,name=PeerGroups,proto3" json:"PeerGroups,omitempty"`
+	PeerGroups  []string `protobuf:"bytes,6,rep,name=PeerGroups,proto3" json:"PeerGroups,omitempty"`
+	RateLimit   float64  `protobuf:"fixed64,7,opt,name=RateLimit,proto3" json:"RateLimit,omitempty"`
+	Burst       int32    `protobuf:"varint,8,opt,name=Burst,proto3" json:"Burst,omitempty"`
+	MaxInFlight int32    `protobuf:"varint,9,opt,name=MaxInFlight,proto3" json:"MaxInFlight,omitempty"`
+	// Compression is the algorithm the service would like for its data requests
+	Compression string `protobuf:"bytes,10,opt,name=Compression,proto3" json:"Compression,omitempty"`
+	// Port is the port the service would like to be assigned, zero for any
+	Port                 int32    `protobuf:"varint,11,opt,name=Port,proto3" json:"Port,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -778,7 +1108,7 @@ func (m *NewService) Reset()         { *m = NewService{} }
 func (m *NewService) String() string { return proto.CompactTextString(m) }
 func (*NewService) ProtoMessage()    {}
 func (*NewService) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{13}
+	return fileDescriptor_22f1d98c525e71fb, []int{18}
 }
 
 func (m *NewService) XXX_Unmarshal(b []byte) error {
@@ -834,10 +1164,53 @@ func (m *NewService) GetPeerGroups() []string {
 	return nil
 }
 
+func (m *NewService) GetRateLimit() float64 {
+	if m != nil {
+		return m.RateLimit
+	}
+	return 0
+}
+
+func (m *NewService) GetBurst() int32 {
+	if m != nil {
+		return m.Burst
+	}
+	return 0
+}
+
+func (m *NewService) GetMaxInFlight() int32 {
+	if m != nil {
+		return m.MaxInFlight
+	}
+	return 0
+}
+
+func (m *NewService) GetCompression() string {
+	if m != nil {
+		return m.Compression
+	}
+	return ""
+}
+
+func (m *NewService) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
 type ServiceSummary struct {
-	Address              string   `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
-	ID                   string   `protobuf:"bytes,2,opt,name=ID,proto3" json:"ID,omitempty"`
-	Fingerprint          string   `protobuf:"bytes,3,opt,name=Fingerprint,proto3" json:"Fingerprint,omitempty"`
+	Address     string `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	ID          string `protobuf:"bytes,2,opt,name=ID,proto3" json:"ID,omitempty"`
+	Fingerprint string `protobuf:"bytes,3,opt,name=Fingerprint,proto3" json:"Fingerprint,omitempty"`
+	// Compression is the algorithm agreed on at registration, empty for none
+	Compression          string `protobuf:"bytes,4,opt,name=Compression,proto3" json:"Compression,omitempty"`
+	CompressionThreshold int32  `protobuf:"varint,5,opt,name=CompressionThreshold,proto3" json:"CompressionThreshold,omitempty"`
+	// BindHost is the host to listen on at the port of Address, empty to listen on Address
+	BindHost string `protobuf:"bytes,6,opt,name=BindHost,proto3" json:"BindHost,omitempty"`
+	// Draining is true if an admin has drained the service, it stays drained when it
+	// registers again until it is undrained
+	Draining             bool     `protobuf:"varint,7,opt,name=Draining,proto3" json:"Draining,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -847,7 +1220,7 @@ func (m *ServiceSummary) Reset()         { *m = ServiceSummary{} }
 func (m *ServiceSummary) String() string { return proto.CompactTextString(m) }
 func (*ServiceSummary) ProtoMessage()    {}
 func (*ServiceSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{14}
+	return fileDescriptor_22f1d98c525e71fb, []int{19}
 }
 
 func (m *ServiceSummary) XXX_Unmarshal(b []byte) error {
@@ -866,29 +1239,180 @@ func (m *ServiceSummary) XXX_DiscardUnknown() {
 	xxx_messageInfo_ServiceSummary.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ServiceSummary proto.InternalMessageInfo
-
-func (m *ServiceSummary) GetAddress() string {
+var xxx_messageInfo_ServiceSummary proto.InternalMessageInfo
+
+func (m *ServiceSummary) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *ServiceSummary) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *ServiceSummary) GetFingerprint() string {
+	if m != nil {
+		return m.Fingerprint
+	}
+	return ""
+}
+
+func (m *ServiceSummary) GetCompression() string {
+	if m != nil {
+		return m.Compression
+	}
+	return ""
+}
+
+func (m *ServiceSummary) GetCompressionThreshold() int32 {
+	if m != nil {
+		return m.CompressionThreshold
+	}
+	return 0
+}
+
+func (m *ServiceSummary) GetBindHost() string {
+	if m != nil {
+		return m.BindHost
+	}
+	return ""
+}
+
+func (m *ServiceSummary) GetDraining() bool {
+	if m != nil {
+		return m.Draining
+	}
+	return false
+}
+
+// ServiceDetail is what core knows of one service, times are RFC3339
+type ServiceDetail struct {
+	ID         string   `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name       string   `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Address    string   `protobuf:"bytes,3,opt,name=Address,proto3" json:"Address,omitempty"`
+	Aliases    []string `protobuf:"bytes,4,rep,name=Aliases,proto3" json:"Aliases,omitempty"`
+	PeerGroups []string `protobuf:"bytes,5,rep,name=PeerGroups,proto3" json:"PeerGroups,omitempty"`
+	State      string   `protobuf:"bytes,6,opt,name=State,proto3" json:"State,omitempty"`
+	// Added is when the service was first registered and its fingerprint issued
+	Added    string `protobuf:"bytes,7,opt,name=Added,proto3" json:"Added,omitempty"`
+	LastPing string `protobuf:"bytes,8,opt,name=LastPing,proto3" json:"LastPing,omitempty"`
+	// InFlight is the number of requests forwarded by core that are waiting on a response
+	InFlight int32 `protobuf:"varint,9,opt,name=InFlight,proto3" json:"InFlight,omitempty"`
+	// Draining is set while core does not route requests to the service
+	Draining             bool     `protobuf:"varint,10,opt,name=Draining,proto3" json:"Draining,omitempty"`
+	Health               *Health  `protobuf:"bytes,11,opt,name=Health,proto3" json:"Health,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServiceDetail) Reset()         { *m = ServiceDetail{} }
+func (m *ServiceDetail) String() string { return proto.CompactTextString(m) }
+func (*ServiceDetail) ProtoMessage()    {}
+func (*ServiceDetail) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22f1d98c525e71fb, []int{20}
+}
+
+func (m *ServiceDetail) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceDetail.Unmarshal(m, b)
+}
+func (m *ServiceDetail) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceDetail.Marshal(b, m, deterministic)
+}
+func (m *ServiceDetail) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceDetail.Merge(m, src)
+}
+func (m *ServiceDetail) XXX_Size() int {
+	return xxx_messageInfo_ServiceDetail.Size(m)
+}
+func (m *ServiceDetail) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceDetail.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiceDetail proto.InternalMessageInfo
+
+func (m *ServiceDetail) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *ServiceDetail) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ServiceDetail) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *ServiceDetail) GetAliases() []string {
+	if m != nil {
+		return m.Aliases
+	}
+	return nil
+}
+
+func (m *ServiceDetail) GetPeerGroups() []string {
+	if m != nil {
+		return m.PeerGroups
+	}
+	return nil
+}
+
+func (m *ServiceDetail) GetState() string {
 	if m != nil {
-		return m.Address
+		return m.State
 	}
 	return ""
 }
 
-func (m *ServiceSummary) GetID() string {
+func (m *ServiceDetail) GetAdded() string {
 	if m != nil {
-		return m.ID
+		return m.Added
 	}
 	return ""
 }
 
-func (m *ServiceSummary) GetFingerprint() string {
+func (m *ServiceDetail) GetLastPing() string {
 	if m != nil {
-		return m.Fingerprint
+		return m.LastPing
 	}
 	return ""
 }
 
+func (m *ServiceDetail) GetInFlight() int32 {
+	if m != nil {
+		return m.InFlight
+	}
+	return 0
+}
+
+func (m *ServiceDetail) GetDraining() bool {
+	if m != nil {
+		return m.Draining
+	}
+	return false
+}
+
+func (m *ServiceDetail) GetHealth() *Health {
+	if m != nil {
+		return m.Health
+	}
+	return nil
+}
+
 type Service struct {
 	Id   string `protobuf:"bytes,10,opt,name=Id,proto3" json:"Id,omitempty"`
 	Name string `protobuf:"bytes,11,opt,name=Name,proto3" json:"Name,omitempty"`
@@ -914,7 +1438,7 @@ func (m *Service) Reset()         { *m = Service{} }
 func (m *Service) String() string { return proto.CompactTextString(m) }
 func (*Service) ProtoMessage()    {}
 func (*Service) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{15}
+	return fileDescriptor_22f1d98c525e71fb, []int{21}
 }
 
 func (m *Service) XXX_Unmarshal(b []byte) error {
@@ -1038,19 +1562,21 @@ type CoreService struct {
 	Address string `protobuf:"bytes,2,opt,name=Address,proto3" json:"Address,omitempty"`
 	Mode    string `protobuf:"bytes,3,opt,name=Mode,proto3" json:"Mode,omitempty"`
 	// string GroupName = 6;
-	PeerGroups           []string `protobuf:"bytes,7,rep,name=PeerGroups,proto3" json:"PeerGroups,omitempty"`
-	ParentID             string   `protobuf:"bytes,5,opt,name=ParentID,proto3" json:"ParentID,omitempty"`
-	Errors               []string `protobuf:"bytes,4,rep,name=Errors,proto3" json:"Errors,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	PeerGroups           []string         `protobuf:"bytes,7,rep,name=PeerGroups,proto3" json:"PeerGroups,omitempty"`
+	ParentID             string           `protobuf:"bytes,5,opt,name=ParentID,proto3" json:"ParentID,omitempty"`
+	Errors               []string         `protobuf:"bytes,4,rep,name=Errors,proto3" json:"Errors,omitempty"`
+	Metrics              map[string]int64 `protobuf:"bytes,8,rep,name=Metrics,proto3" json:"Metrics,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Health               *Health          `protobuf:"bytes,9,opt,name=Health,proto3" json:"Health,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *CoreService) Reset()         { *m = CoreService{} }
 func (m *CoreService) String() string { return proto.CompactTextString(m) }
 func (*CoreService) ProtoMessage()    {}
 func (*CoreService) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{16}
+	return fileDescriptor_22f1d98c525e71fb, []int{22}
 }
 
 func (m *CoreService) XXX_Unmarshal(b []byte) error {
@@ -1113,25 +1639,42 @@ func (m *CoreService) GetErrors() []string {
 	return nil
 }
 
+func (m *CoreService) GetMetrics() map[string]int64 {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+func (m *CoreService) GetHealth() *Health {
+	if m != nil {
+		return m.Health
+	}
+	return nil
+}
+
 //
 //Data Handlers
 type Request struct {
-	Sender               string     `protobuf:"bytes,1,opt,name=Sender,proto3" json:"Sender,omitempty"`
-	Target               string     `protobuf:"bytes,2,opt,name=Target,proto3" json:"Target,omitempty"`
-	Method               string     `protobuf:"bytes,3,opt,name=Method,proto3" json:"Method,omitempty"`
-	Data1                string     `protobuf:"bytes,50,opt,name=Data1,proto3" json:"Data1,omitempty"`
-	Tport                *Transport `protobuf:"bytes,55,opt,name=Tport,proto3" json:"Tport,omitempty"`
-	Pload                *Payload   `protobuf:"bytes,60,opt,name=Pload,proto3" json:"Pload,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	Sender string     `protobuf:"bytes,1,opt,name=Sender,proto3" json:"Sender,omitempty"`
+	Target string     `protobuf:"bytes,2,opt,name=Target,proto3" json:"Target,omitempty"`
+	Method string     `protobuf:"bytes,3,opt,name=Method,proto3" json:"Method,omitempty"`
+	Data1  string     `protobuf:"bytes,50,opt,name=Data1,proto3" json:"Data1,omitempty"`
+	Tport  *Transport `protobuf:"bytes,55,opt,name=Tport,proto3" json:"Tport,omitempty"`
+	Pload  *Payload   `protobuf:"bytes,60,opt,name=Pload,proto3" json:"Pload,omitempty"`
+	// Codec of the encoded payload values, it is also the codec the sender would like in
+	// the response. Empty means json.
+	Codec                string   `protobuf:"bytes,70,opt,name=Codec,proto3" json:"Codec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Request) Reset()         { *m = Request{} }
 func (m *Request) String() string { return proto.CompactTextString(m) }
 func (*Request) ProtoMessage()    {}
 func (*Request) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{17}
+	return fileDescriptor_22f1d98c525e71fb, []int{23}
 }
 
 func (m *Request) XXX_Unmarshal(b []byte) error {
@@ -1194,23 +1737,32 @@ func (m *Request) GetPload() *Payload {
 	return nil
 }
 
+func (m *Request) GetCodec() string {
+	if m != nil {
+		return m.Codec
+	}
+	return ""
+}
+
 type Responder struct {
-	Result               string     `protobuf:"bytes,50,opt,name=Result,proto3" json:"Result,omitempty"`
-	ErrorString          string     `protobuf:"bytes,98,opt,name=ErrorString,proto3" json:"ErrorString,omitempty"`
-	HadError             bool       `protobuf:"varint,99,opt,name=HadError,proto3" json:"HadError,omitempty"`
-	Tport                *Transport `protobuf:"bytes,55,opt,name=Tport,proto3" json:"Tport,omitempty"`
-	Pload                *Payload   `protobuf:"bytes,60,opt,name=Pload,proto3" json:"Pload,omitempty"`
-	Err                  string     `protobuf:"bytes,65,opt,name=Err,proto3" json:"Err,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	Result      string     `protobuf:"bytes,50,opt,name=Result,proto3" json:"Result,omitempty"`
+	ErrorString string     `protobuf:"bytes,98,opt,name=ErrorString,proto3" json:"ErrorString,omitempty"`
+	HadError    bool       `protobuf:"varint,99,opt,name=HadError,proto3" json:"HadError,omitempty"`
+	Tport       *Transport `protobuf:"bytes,55,opt,name=Tport,proto3" json:"Tport,omitempty"`
+	Pload       *Payload   `protobuf:"bytes,60,opt,name=Pload,proto3" json:"Pload,omitempty"`
+	Err         string     `protobuf:"bytes,65,opt,name=Err,proto3" json:"Err,omitempty"`
+	// Codec of the encoded payload values, empty means json
+	Codec                string   `protobuf:"bytes,70,opt,name=Codec,proto3" json:"Codec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Responder) Reset()         { *m = Responder{} }
 func (m *Responder) String() string { return proto.CompactTextString(m) }
 func (*Responder) ProtoMessage()    {}
 func (*Responder) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{18}
+	return fileDescriptor_22f1d98c525e71fb, []int{24}
 }
 
 func (m *Responder) XXX_Unmarshal(b []byte) error {
@@ -1273,6 +1825,13 @@ func (m *Responder) GetErr() string {
 	return ""
 }
 
+func (m *Responder) GetCodec() string {
+	if m != nil {
+		return m.Codec
+	}
+	return ""
+}
+
 type Transport struct {
 	Sender               string   `protobuf:"bytes,1,opt,name=Sender,proto3" json:"Sender,omitempty"`
 	Target               string   `protobuf:"bytes,2,opt,name=Target,proto3" json:"Target,omitempty"`
@@ -1286,7 +1845,7 @@ func (m *Transport) Reset()         { *m = Transport{} }
 func (m *Transport) String() string { return proto.CompactTextString(m) }
 func (*Transport) ProtoMessage()    {}
 func (*Transport) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{19}
+	return fileDescriptor_22f1d98c525e71fb, []int{25}
 }
 
 func (m *Transport) XXX_Unmarshal(b []byte) error {
@@ -1329,28 +1888,30 @@ func (m *Transport) GetMethod() string {
 }
 
 type Payload struct {
-	Fields               map[string]*SubFields `protobuf:"bytes,50,rep,name=Fields,proto3" json:"Fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	JSON                 map[string][]byte     `protobuf:"bytes,55,rep,name=JSON,proto3" json:"JSON,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	TextFields           map[string]string     `protobuf:"bytes,60,rep,name=TextFields,proto3" json:"TextFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	BoolFields           map[string]bool       `protobuf:"bytes,62,rep,name=BoolFields,proto3" json:"BoolFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	ByteFields           map[string][]byte     `protobuf:"bytes,64,rep,name=ByteFields,proto3" json:"ByteFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	IntFields            map[string]int32      `protobuf:"bytes,66,rep,name=IntFields,proto3" json:"IntFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	Int64Fields          map[string]int64      `protobuf:"bytes,68,rep,name=Int64Fields,proto3" json:"Int64Fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	UintFields           map[string]uint32     `protobuf:"bytes,70,rep,name=UintFields,proto3" json:"UintFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	Uint64Fields         map[string]uint64     `protobuf:"bytes,72,rep,name=Uint64Fields,proto3" json:"Uint64Fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
-	DoubleFields         map[string]float64    `protobuf:"bytes,74,rep,name=DoubleFields,proto3" json:"DoubleFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
-	FloatFields          map[string]float32    `protobuf:"bytes,76,rep,name=FloatFields,proto3" json:"FloatFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed32,2,opt,name=value,proto3"`
-	Data                 map[string]*any.Any   `protobuf:"bytes,80,rep,name=Data,proto3" json:"Data,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	Fields       map[string]*SubFields `protobuf:"bytes,50,rep,name=Fields,proto3" json:"Fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	JSON         map[string][]byte     `protobuf:"bytes,55,rep,name=JSON,proto3" json:"JSON,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	TextFields   map[string]string     `protobuf:"bytes,60,rep,name=TextFields,proto3" json:"TextFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	BoolFields   map[string]bool       `protobuf:"bytes,62,rep,name=BoolFields,proto3" json:"BoolFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	ByteFields   map[string][]byte     `protobuf:"bytes,64,rep,name=ByteFields,proto3" json:"ByteFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	IntFields    map[string]int32      `protobuf:"bytes,66,rep,name=IntFields,proto3" json:"IntFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Int64Fields  map[string]int64      `protobuf:"bytes,68,rep,name=Int64Fields,proto3" json:"Int64Fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	UintFields   map[string]uint32     `protobuf:"bytes,70,rep,name=UintFields,proto3" json:"UintFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Uint64Fields map[string]uint64     `protobuf:"bytes,72,rep,name=Uint64Fields,proto3" json:"Uint64Fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	DoubleFields map[string]float64    `protobuf:"bytes,74,rep,name=DoubleFields,proto3" json:"DoubleFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	FloatFields  map[string]float32    `protobuf:"bytes,76,rep,name=FloatFields,proto3" json:"FloatFields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed32,2,opt,name=value,proto3"`
+	Data         map[string]*any.Any   `protobuf:"bytes,80,rep,name=Data,proto3" json:"Data,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Encoded holds the values marshalled with the codec named in the Request or Responder
+	Encoded              map[string][]byte `protobuf:"bytes,82,rep,name=Encoded,proto3" json:"Encoded,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *Payload) Reset()         { *m = Payload{} }
 func (m *Payload) String() string { return proto.CompactTextString(m) }
 func (*Payload) ProtoMessage()    {}
 func (*Payload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{20}
+	return fileDescriptor_22f1d98c525e71fb, []int{26}
 }
 
 func (m *Payload) XXX_Unmarshal(b []byte) error {
@@ -1455,6 +2016,13 @@ func (m *Payload) GetData() map[string]*any.Any {
 	return nil
 }
 
+func (m *Payload) GetEncoded() map[string][]byte {
+	if m != nil {
+		return m.Encoded
+	}
+	return nil
+}
+
 type SubFields struct {
 	Sub                  []string `protobuf:"bytes,1,rep,name=Sub,proto3" json:"Sub,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -1466,7 +2034,7 @@ func (m *SubFields) Reset()         { *m = SubFields{} }
 func (m *SubFields) String() string { return proto.CompactTextString(m) }
 func (*SubFields) ProtoMessage()    {}
 func (*SubFields) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22f1d98c525e71fb, []int{21}
+	return fileDescriptor_22f1d98c525e71fb, []int{27}
 }
 
 func (m *SubFields) XXX_Unmarshal(b []byte) error {
@@ -1499,19 +2067,27 @@ func init() {
 	proto.RegisterType((*Receipt)(nil), "intrigue.Receipt")
 	proto.RegisterType((*DataRequest)(nil), "intrigue.DataRequest")
 	proto.RegisterType((*DataResponse)(nil), "intrigue.DataResponse")
+	proto.RegisterType((*StreamChunk)(nil), "intrigue.StreamChunk")
 	proto.RegisterType((*WhoIsRequest)(nil), "intrigue.WhoIsRequest")
 	proto.RegisterType((*WhoIsResponse)(nil), "intrigue.WhoIsResponse")
 	proto.RegisterType((*EmptyRequest)(nil), "intrigue.EmptyRequest")
 	proto.RegisterType((*ServiceUpdate)(nil), "intrigue.ServiceUpdate")
 	proto.RegisterType((*Action)(nil), "intrigue.Action")
 	proto.RegisterType((*SummaryReceipt)(nil), "intrigue.SummaryReceipt")
+	proto.RegisterType((*InspectReceipt)(nil), "intrigue.InspectReceipt")
+	proto.RegisterType((*LogRequest)(nil), "intrigue.LogRequest")
+	proto.RegisterType((*LogLine)(nil), "intrigue.LogLine")
 	proto.RegisterType((*Ping)(nil), "intrigue.Ping")
 	proto.RegisterType((*Pong)(nil), "intrigue.Pong")
+	proto.RegisterType((*Health)(nil), "intrigue.Health")
+	proto.RegisterMapType((map[string]string)(nil), "intrigue.Health.DetailsEntry")
 	proto.RegisterType((*ProcessManager)(nil), "intrigue.ProcessManager")
 	proto.RegisterType((*NewService)(nil), "intrigue.NewService")
 	proto.RegisterType((*ServiceSummary)(nil), "intrigue.ServiceSummary")
+	proto.RegisterType((*ServiceDetail)(nil), "intrigue.ServiceDetail")
 	proto.RegisterType((*Service)(nil), "intrigue.Service")
 	proto.RegisterType((*CoreService)(nil), "intrigue.CoreService")
+	proto.RegisterMapType((map[string]int64)(nil), "intrigue.CoreService.MetricsEntry")
 	proto.RegisterType((*Request)(nil), "intrigue.Request")
 	proto.RegisterType((*Responder)(nil), "intrigue.Responder")
 	proto.RegisterType((*Transport)(nil), "intrigue.Transport")
@@ -1520,6 +2096,7 @@ func init() {
 	proto.RegisterMapType((map[string][]byte)(nil), "intrigue.Payload.ByteFieldsEntry")
 	proto.RegisterMapType((map[string]*any.Any)(nil), "intrigue.Payload.DataEntry")
 	proto.RegisterMapType((map[string]float64)(nil), "intrigue.Payload.DoubleFieldsEntry")
+	proto.RegisterMapType((map[string][]byte)(nil), "intrigue.Payload.EncodedEntry")
 	proto.RegisterMapType((map[string]*SubFields)(nil), "intrigue.Payload.FieldsEntry")
 	proto.RegisterMapType((map[string]float32)(nil), "intrigue.Payload.FloatFieldsEntry")
 	proto.RegisterMapType((map[string]int64)(nil), "intrigue.Payload.Int64FieldsEntry")
@@ -1534,105 +2111,140 @@ func init() {
 func init() { proto.RegisterFile("intrigue.proto", fileDescriptor_22f1d98c525e71fb) }
 
 var fileDescriptor_22f1d98c525e71fb = []byte{
-	// 1559 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x58, 0xdd, 0x72, 0xdb, 0xc4,
-	0x17, 0x8f, 0xfc, 0x11, 0xc7, 0xc7, 0x8e, 0x9b, 0x6c, 0xd3, 0x56, 0x75, 0xff, 0xfd, 0x4f, 0x10,
-	0xcc, 0x50, 0x60, 0x48, 0xa7, 0xa6, 0x25, 0xd0, 0x29, 0x81, 0x34, 0x4e, 0x5a, 0xb7, 0x4d, 0xf0,
-	0xc8, 0xe9, 0xf4, 0x86, 0x9b, 0x75, 0xbc, 0x55, 0x35, 0x28, 0x5a, 0xb3, 0x5a, 0xa7, 0xf8, 0x01,
-	0xe0, 0x8e, 0x0b, 0xfa, 0x04, 0xcc, 0xf0, 0x18, 0x3c, 0x00, 0x0f, 0xc0, 0x33, 0xc0, 0x0c, 0x6f,
-	0xc1, 0xec, 0x87, 0xa4, 0x95, 0x64, 0xd7, 0x13, 0x28, 0x17, 0xdc, 0xed, 0x39, 0x7b, 0xce, 0x6f,
-	0xcf, 0xc7, 0x6f, 0x77, 0xb5, 0x82, 0x96, 0x1f, 0x72, 0xe6, 0x7b, 0x13, 0xb2, 0x35, 0x66, 0x94,
-	0x53, 0xb4, 0x12, 0xcb, 0xed, 0xab, 0x1e, 0xa5, 0x5e, 0x40, 0x6e, 0x4a, 0xfd, 0x70, 0xf2, 0xfc,
-	0x26, 0x0e, 0xa7, 0xca, 0xc8, 0xa1, 0xb0, 0x7e, 0x44, 0x5e, 0x0e, 0x08, 0x3b, 0xf3, 0x4f, 0x88,
-	0x4b, 0xbe, 0x99, 0x90, 0x88, 0xa3, 0x2d, 0xa8, 0x69, 0x8d, 0x6d, 0x6d, 0x5a, 0x37, 0x1a, 0x9d,
-	0x8d, 0xad, 0x04, 0xdb, 0xb0, 0x8e, 0x8d, 0x90, 0x0d, 0xb5, 0xdd, 0xd1, 0x88, 0x91, 0x28, 0xb2,
-	0x4b, 0x9b, 0xd6, 0x8d, 0xba, 0x1b, 0x8b, 0x68, 0x0d, 0xca, 0xfb, 0xe1, 0x99, 0x5d, 0x96, 0x5a,
-	0x31, 0x74, 0x7e, 0xb4, 0xa0, 0xe6, 0x92, 0x13, 0xe2, 0x8f, 0x39, 0xba, 0x0b, 0x8d, 0x48, 0x41,
-	0xf4, 0xc2, 0xe7, 0x54, 0xaf, 0x65, 0xa7, 0x6b, 0x69, 0xfc, 0xc1, 0xe4, 0xf4, 0x14, 0xb3, 0xa9,
-	0x6b, 0x1a, 0x8b, 0x35, 0x0f, 0x49, 0x14, 0x61, 0x8f, 0xc4, 0x6b, 0x6a, 0x11, 0xb5, 0x61, 0xe5,
-	0x80, 0x06, 0x01, 0x7d, 0x39, 0x19, 0xeb, 0x85, 0x13, 0x19, 0x6d, 0x40, 0x75, 0x9f, 0x31, 0xca,
-	0x6c, 0x90, 0x13, 0x4a, 0x70, 0xfa, 0xd0, 0xe8, 0x62, 0x8e, 0xe3, 0xf4, 0x3f, 0x10, 0x11, 0xca,
-	0xa1, 0x0e, 0x69, 0x3d, 0x0d, 0x49, 0x4f, 0xb8, 0xb1, 0x45, 0x8a, 0x58, 0x32, 0x11, 0x9f, 0x41,
-	0x53, 0x21, 0x46, 0x63, 0x1a, 0x46, 0x04, 0xdd, 0x82, 0xba, 0x1a, 0x8f, 0x88, 0xb2, 0x6c, 0x74,
-	0x2e, 0x9a, 0xa0, 0x7a, 0xca, 0x4d, 0xad, 0x52, 0xe0, 0xb2, 0x09, 0xbc, 0x03, 0xcd, 0x67, 0x2f,
-	0x68, 0x2f, 0x8a, 0x97, 0xbf, 0x0c, 0xcb, 0x03, 0x22, 0x51, 0x2d, 0x69, 0xa6, 0x25, 0xa1, 0x3f,
-	0xc6, 0xcc, 0x23, 0x5c, 0xc7, 0xa5, 0x25, 0xe7, 0x31, 0xac, 0x6a, 0x7f, 0x1d, 0xd9, 0x3b, 0xb0,
-	0xaa, 0xa6, 0xe2, 0x0e, 0x2a, 0x9c, 0xac, 0x72, 0x4e, 0x30, 0x2d, 0x68, 0xee, 0x9f, 0x8e, 0xf9,
-	0x54, 0x07, 0xe3, 0x7c, 0x67, 0xc1, 0xaa, 0xee, 0xd9, 0xd3, 0xf1, 0x08, 0x73, 0xc9, 0x0c, 0xb3,
-	0x94, 0xf5, 0xb4, 0x6e, 0xf3, 0xfb, 0x67, 0xb0, 0xa9, 0x32, 0x93, 0x4d, 0xd5, 0x84, 0x4d, 0x73,
-	0xe2, 0xfa, 0xde, 0x82, 0xe5, 0xdd, 0x13, 0xee, 0xd3, 0xf0, 0x35, 0x01, 0xcc, 0xa9, 0x90, 0xa0,
-	0x8f, 0x4b, 0x4e, 0x29, 0x27, 0xbd, 0xae, 0x5e, 0x29, 0x91, 0xcd, 0xa0, 0x2b, 0xd9, 0xa0, 0x67,
-	0x07, 0xf2, 0x83, 0x05, 0xad, 0x98, 0xbd, 0x9a, 0xf3, 0x1d, 0x11, 0x90, 0x80, 0x13, 0x95, 0x2e,
-	0x67, 0xf9, 0xde, 0x67, 0xf4, 0x84, 0x44, 0xd1, 0x21, 0x0e, 0xb1, 0x47, 0x98, 0x1b, 0x1b, 0xa2,
-	0x5b, 0xb0, 0xa2, 0xcb, 0x2a, 0x4a, 0x22, 0x9c, 0x2e, 0xa5, 0x4e, 0x7b, 0x94, 0x91, 0x78, 0x47,
-	0x26, 0x66, 0x73, 0xe2, 0x79, 0x08, 0x95, 0xbe, 0x1f, 0x7a, 0x92, 0x35, 0x1c, 0xf3, 0x49, 0x94,
-	0xb0, 0x46, 0x4a, 0x08, 0x41, 0xe5, 0xd8, 0x3f, 0x8d, 0x3b, 0x22, 0xc7, 0xaf, 0x41, 0xa2, 0x6f,
-	0x04, 0xe9, 0x77, 0x0b, 0x5a, 0xd9, 0xc4, 0x51, 0x0b, 0x4a, 0xbd, 0xae, 0x06, 0x2c, 0xf5, 0xba,
-	0x02, 0xec, 0x08, 0xa7, 0x60, 0x62, 0x6c, 0xb2, 0xa4, 0x9c, 0x65, 0xc9, 0xff, 0xa0, 0x3e, 0xe0,
-	0x98, 0x71, 0xb9, 0xbe, 0xea, 0x60, 0xaa, 0x10, 0x01, 0xcb, 0x75, 0x23, 0x7b, 0x65, 0xb3, 0x2c,
-	0x02, 0x56, 0x92, 0x91, 0x48, 0x2d, 0x93, 0x88, 0x0d, 0xb5, 0x27, 0xd4, 0xeb, 0x63, 0xfe, 0xc2,
-	0xae, 0xab, 0x75, 0xb4, 0x88, 0x3e, 0x2c, 0x74, 0x65, 0xbd, 0x70, 0x74, 0xa5, 0x1d, 0x71, 0x5e,
-	0x59, 0x00, 0xe9, 0xe1, 0x99, 0xe4, 0x64, 0xe5, 0x72, 0x0a, 0x7c, 0x1c, 0x11, 0x71, 0x8e, 0x96,
-	0x65, 0x4e, 0x4a, 0x14, 0xa4, 0xec, 0x45, 0xc2, 0x95, 0xa8, 0xea, 0xad, 0xb8, 0x89, 0xac, 0xe6,
-	0xf6, 0x02, 0x9f, 0x84, 0x5c, 0xb2, 0x52, 0xce, 0x29, 0x19, 0xfd, 0x1f, 0xa0, 0x4f, 0x08, 0x7b,
-	0xc0, 0xe8, 0x64, 0x1c, 0xd9, 0xcb, 0x12, 0xd4, 0xd0, 0x38, 0x5f, 0x41, 0x2b, 0x7b, 0xc8, 0x9a,
-	0x75, 0xb5, 0xb2, 0x75, 0x55, 0x5d, 0x29, 0x25, 0x5d, 0xd9, 0x84, 0xc6, 0x81, 0x1f, 0x7a, 0x84,
-	0x8d, 0x99, 0x1f, 0x72, 0xdd, 0x05, 0x53, 0xe5, 0xfc, 0x56, 0x4a, 0x2e, 0x12, 0xe9, 0x3d, 0xd2,
-	0xc7, 0x6e, 0xa9, 0x37, 0x4a, 0xf2, 0x6f, 0x18, 0xf9, 0x23, 0xa8, 0x1c, 0xd2, 0x11, 0xb1, 0xaf,
-	0x28, 0x9d, 0x18, 0x9b, 0xf1, 0x5c, 0xca, 0xc6, 0x83, 0xa0, 0x22, 0xdb, 0xd2, 0x54, 0xd6, 0xb2,
-	0x27, 0x46, 0xb7, 0x36, 0xb2, 0xdd, 0x4a, 0xfb, 0xdb, 0xca, 0xf4, 0x57, 0x6e, 0xf7, 0x48, 0xd0,
-	0x23, 0xb2, 0x2f, 0x6c, 0x5a, 0x37, 0xaa, 0x6e, 0x22, 0x0b, 0xc2, 0x1e, 0x60, 0x3f, 0x88, 0x6c,
-	0x5b, 0x4e, 0x28, 0x41, 0x9c, 0x42, 0x7d, 0x7f, 0x64, 0xaf, 0x49, 0x9d, 0x18, 0x66, 0x19, 0xb7,
-	0x9e, 0x67, 0x9c, 0xb8, 0x8f, 0xb0, 0x1f, 0xc8, 0x49, 0xa4, 0xef, 0x23, 0x2d, 0x8b, 0xb9, 0x27,
-	0x38, 0xf4, 0x26, 0xe2, 0x44, 0xb9, 0xaa, 0xe6, 0x62, 0xd9, 0x60, 0xea, 0x45, 0x93, 0xa9, 0xce,
-	0xcf, 0x16, 0x34, 0x8c, 0x4d, 0x3f, 0x97, 0x49, 0xb3, 0x6f, 0xe4, 0xb8, 0xc6, 0x65, 0xa3, 0xc6,
-	0x59, 0x96, 0xd4, 0xf2, 0x2c, 0x11, 0x51, 0xf6, 0x31, 0x23, 0x21, 0x4f, 0x8f, 0xc4, 0x58, 0x36,
-	0xa2, 0xac, 0x64, 0xa2, 0xfc, 0xc5, 0x82, 0xda, 0xdf, 0xbc, 0xa4, 0x84, 0xfe, 0x90, 0xf0, 0x17,
-	0x74, 0xa4, 0xa3, 0xd4, 0x92, 0xe8, 0x87, 0xb8, 0x55, 0x6f, 0xd9, 0x1d, 0x75, 0x80, 0x48, 0x01,
-	0xbd, 0x07, 0xd5, 0xe3, 0x31, 0x65, 0xdc, 0xde, 0xce, 0xdf, 0xab, 0xc7, 0x0c, 0x87, 0x91, 0x98,
-	0x72, 0x95, 0x05, 0x7a, 0x17, 0xaa, 0xfd, 0x80, 0xe2, 0x91, 0x7d, 0x2f, 0x7f, 0xaf, 0xf7, 0xf1,
-	0x54, 0x4c, 0xb8, 0x6a, 0xde, 0xf9, 0xd5, 0x32, 0x2e, 0x6c, 0x11, 0x8f, 0x4b, 0xa2, 0x49, 0xc0,
-	0xf5, 0xc2, 0x5a, 0x12, 0x3b, 0x40, 0x66, 0x3b, 0xe0, 0xcc, 0x0f, 0x3d, 0x7b, 0xa8, 0x76, 0x80,
-	0xa1, 0x12, 0x95, 0x7b, 0x88, 0x47, 0xea, 0xd4, 0x3b, 0x51, 0x7b, 0x33, 0x96, 0xff, 0x8d, 0xb8,
-	0xe5, 0x0d, 0xc9, 0x98, 0xbd, 0xab, 0x6f, 0x48, 0xc6, 0x9c, 0x01, 0xd4, 0x13, 0xb8, 0x37, 0xd5,
-	0x08, 0xe7, 0xa7, 0x26, 0xd4, 0xf4, 0xca, 0xe8, 0x0e, 0x2c, 0x1f, 0xf8, 0x24, 0x18, 0x45, 0x76,
-	0x47, 0x1e, 0x82, 0xd7, 0x0b, 0xc1, 0x6d, 0xa9, 0xf9, 0xfd, 0x90, 0xb3, 0xa9, 0xab, 0x8d, 0xd1,
-	0x4d, 0xa8, 0x3c, 0x1a, 0x7c, 0x79, 0x64, 0x6f, 0x4b, 0xa7, 0x6b, 0x45, 0x27, 0x31, 0xab, 0x5c,
-	0xa4, 0x21, 0xda, 0x05, 0x38, 0x26, 0xdf, 0x72, 0xbd, 0xd6, 0x3d, 0xe9, 0xf6, 0x56, 0xd1, 0x2d,
-	0xb5, 0x51, 0xce, 0x86, 0x93, 0x80, 0xb8, 0x4f, 0x69, 0xa0, 0x21, 0x76, 0xe6, 0x41, 0xa4, 0x36,
-	0x1a, 0x22, 0x55, 0x48, 0x88, 0x29, 0x27, 0x1a, 0xe2, 0x8b, 0xb9, 0x10, 0x89, 0x4d, 0x0c, 0x91,
-	0x28, 0xd0, 0x0e, 0xd4, 0x7b, 0x61, 0x9c, 0xc7, 0x7d, 0x89, 0xb0, 0x59, 0x44, 0x48, 0x4c, 0x14,
-	0x40, 0xea, 0x82, 0xba, 0xd0, 0xe8, 0x85, 0xfc, 0xe3, 0xdb, 0x1a, 0xa1, 0x2b, 0x11, 0x9c, 0x99,
-	0x08, 0xb1, 0x91, 0xc2, 0x30, 0xdd, 0x44, 0x22, 0x4f, 0xfd, 0x24, 0x8c, 0x83, 0x79, 0x89, 0xa4,
-	0x36, 0x3a, 0x91, 0x54, 0x81, 0x1e, 0x40, 0x53, 0x48, 0x49, 0x24, 0x0f, 0x25, 0xc8, 0xdb, 0xb3,
-	0x41, 0xb2, 0xa1, 0x64, 0x1c, 0x05, 0x50, 0x97, 0x4e, 0x86, 0x41, 0x5c, 0xd6, 0x47, 0xf3, 0x80,
-	0x4c, 0x2b, 0x0d, 0x64, 0xaa, 0x44, 0x69, 0x0e, 0x02, 0x8a, 0xe3, 0xac, 0x9e, 0xcc, 0x2b, 0x8d,
-	0x61, 0xa4, 0x4b, 0x63, 0x68, 0x04, 0x35, 0xc5, 0xc9, 0x62, 0xf7, 0xe7, 0x51, 0x53, 0xcc, 0x6a,
-	0x6a, 0x8a, 0x61, 0xfb, 0x48, 0xdc, 0x84, 0x09, 0x98, 0xd8, 0x84, 0x5f, 0x93, 0xa9, 0xde, 0x62,
-	0x62, 0x28, 0xb6, 0xfa, 0x19, 0x0e, 0x26, 0xa4, 0xf8, 0xe9, 0x1f, 0x4d, 0x86, 0xca, 0xd5, 0x55,
-	0x16, 0x77, 0x4b, 0x9f, 0x58, 0xed, 0x6d, 0xa8, 0x27, 0xec, 0x9f, 0x81, 0xb6, 0x61, 0xa2, 0x35,
-	0x4d, 0xc7, 0xcf, 0xe0, 0x42, 0x8e, 0xff, 0x8b, 0xdc, 0xeb, 0x39, 0xf7, 0x1c, 0xf7, 0x17, 0xb9,
-	0xaf, 0xe4, 0xdd, 0xb3, 0xbc, 0x3f, 0x57, 0xf0, 0xf7, 0xa0, 0x95, 0x25, 0xfd, 0x22, 0xef, 0xaa,
-	0xe9, 0xbd, 0x03, 0x6b, 0x79, 0xc2, 0x2f, 0xf2, 0x2f, 0xe7, 0x82, 0xcf, 0x71, 0x7d, 0x91, 0xfb,
-	0xaa, 0xe9, 0xfe, 0x39, 0xac, 0x17, 0x58, 0xbe, 0x08, 0xa0, 0x92, 0x03, 0x28, 0xb0, 0x7b, 0x11,
-	0x80, 0x95, 0x2b, 0x40, 0x9e, 0xd6, 0x8b, 0xfc, 0x4b, 0xa6, 0xff, 0x21, 0xd4, 0x13, 0x5e, 0xcf,
-	0x70, 0x7c, 0x3f, 0x4b, 0xe1, 0x8d, 0x2d, 0xf5, 0x4f, 0x61, 0x2b, 0xfe, 0xa7, 0xb0, 0xb5, 0x1b,
-	0x4e, 0x0d, 0x38, 0xe7, 0x3a, 0xd4, 0x13, 0x6e, 0x0b, 0xb8, 0xc1, 0x64, 0x28, 0x1f, 0x3c, 0x75,
-	0x57, 0x0c, 0x3b, 0x7f, 0x96, 0xa0, 0xba, 0x87, 0x87, 0x38, 0x40, 0x7b, 0x70, 0xc1, 0x25, 0x9e,
-	0x1f, 0x71, 0xc2, 0xe2, 0x2f, 0x9a, 0x6b, 0x33, 0x7f, 0x37, 0xa8, 0x8f, 0x89, 0x76, 0xe6, 0x31,
-	0x2e, 0xdf, 0x54, 0xce, 0x12, 0xba, 0x0f, 0x48, 0xbd, 0x38, 0x15, 0x14, 0xc3, 0xf2, 0xf1, 0x77,
-	0xa5, 0xf0, 0x3d, 0xae, 0x8c, 0x66, 0x63, 0x6c, 0xab, 0x6d, 0x8f, 0x8c, 0xb7, 0x95, 0xf1, 0x57,
-	0xa0, 0x7d, 0x39, 0xaf, 0x56, 0x0f, 0x68, 0x67, 0x09, 0xdd, 0x85, 0xaa, 0x7c, 0x53, 0x23, 0xc3,
-	0xc4, 0x7c, 0xa4, 0xb7, 0xaf, 0x14, 0xf4, 0x89, 0xef, 0x36, 0xd4, 0xe2, 0x2f, 0xef, 0xb5, 0xd4,
-	0x4a, 0x3d, 0x5e, 0xdb, 0xe6, 0xaf, 0x90, 0xcc, 0x2b, 0xd2, 0x59, 0x12, 0x47, 0xca, 0x6e, 0xe0,
-	0x9f, 0x11, 0xd4, 0x32, 0xce, 0x27, 0x3f, 0xf4, 0xda, 0xa6, 0x4c, 0x43, 0xcf, 0x59, 0xea, 0xfc,
-	0x61, 0x89, 0xef, 0x17, 0xf1, 0x94, 0x44, 0xb7, 0xa1, 0x79, 0x44, 0xb9, 0xff, 0x7c, 0xaa, 0x9f,
-	0xc7, 0xc5, 0x35, 0x0b, 0x9a, 0x7f, 0x12, 0xe4, 0x9b, 0x68, 0xcb, 0x39, 0x12, 0x7d, 0x55, 0x86,
-	0xda, 0x1e, 0x0d, 0x39, 0xa3, 0x01, 0xba, 0x03, 0x4d, 0xf9, 0x09, 0x1e, 0x73, 0xaa, 0x18, 0xf8,
-	0x1c, 0x12, 0xb4, 0xf4, 0xe7, 0xff, 0x39, 0x1d, 0x6f, 0x43, 0xe3, 0xb1, 0x1f, 0x04, 0xe7, 0x5e,
-	0xee, 0x3f, 0x51, 0x59, 0xf4, 0x29, 0xc0, 0x80, 0xd3, 0xb1, 0x7e, 0x71, 0x1a, 0x3c, 0x37, 0xff,
-	0xff, 0xcc, 0x5c, 0x65, 0xb8, 0x2c, 0x4f, 0x88, 0x8f, 0xfe, 0x0a, 0x00, 0x00, 0xff, 0xff, 0x77,
-	0x16, 0xd3, 0x2e, 0x9f, 0x14, 0x00, 0x00,
+	// 2118 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x19, 0x4d, 0x73, 0xdb, 0xc6,
+	0x55, 0xe0, 0x87, 0x48, 0x3e, 0x52, 0xb2, 0xb5, 0x51, 0x6c, 0x84, 0x69, 0x32, 0x2a, 0xda, 0x99,
+	0x2a, 0xed, 0x54, 0x8e, 0x69, 0x3b, 0x4a, 0x35, 0xae, 0x1a, 0x7d, 0x5a, 0x4c, 0x25, 0x95, 0x03,
+	0xca, 0x93, 0xe9, 0x11, 0x22, 0xd6, 0x14, 0xc6, 0x10, 0x96, 0x5d, 0x2c, 0x95, 0xf0, 0x07, 0xb4,
+	0xb7, 0x1e, 0xda, 0x43, 0x2e, 0x9d, 0xe9, 0x7f, 0xe8, 0xaf, 0xc8, 0xb9, 0xd3, 0x63, 0xa7, 0xc7,
+	0x5e, 0xfa, 0x2b, 0x3a, 0x6f, 0x77, 0x01, 0x2c, 0x40, 0x22, 0xaa, 0x62, 0xf7, 0x92, 0x13, 0xf7,
+	0xbd, 0x7d, 0x5f, 0xfb, 0xbe, 0xb0, 0xfb, 0x08, 0xab, 0x41, 0x24, 0x78, 0x30, 0x9e, 0xd2, 0xad,
+	0x09, 0x67, 0x82, 0x91, 0x66, 0x02, 0x77, 0xdf, 0x1b, 0x33, 0x36, 0x0e, 0xe9, 0x23, 0x89, 0xbf,
+	0x9c, 0xbe, 0x7a, 0xe4, 0x45, 0x33, 0x45, 0xe4, 0x30, 0x58, 0x3b, 0xa7, 0x5f, 0x0e, 0x29, 0xbf,
+	0x09, 0x46, 0xd4, 0xa5, 0xbf, 0x9b, 0xd2, 0x58, 0x90, 0x2d, 0x68, 0x68, 0x8c, 0x6d, 0x6d, 0x58,
+	0x9b, 0xed, 0xde, 0xfa, 0x56, 0x2a, 0xdb, 0xa0, 0x4e, 0x88, 0x88, 0x0d, 0x8d, 0x3d, 0xdf, 0xe7,
+	0x34, 0x8e, 0xed, 0xca, 0x86, 0xb5, 0xd9, 0x72, 0x13, 0x90, 0xdc, 0x87, 0xea, 0x51, 0x74, 0x63,
+	0x57, 0x25, 0x16, 0x97, 0xce, 0x9f, 0x2c, 0x68, 0xb8, 0x74, 0x44, 0x83, 0x89, 0x20, 0x3b, 0xd0,
+	0x8e, 0x95, 0x88, 0x7e, 0xf4, 0x8a, 0x69, 0x5d, 0x76, 0xa6, 0x4b, 0xcb, 0x1f, 0x4e, 0xaf, 0xaf,
+	0x3d, 0x3e, 0x73, 0x4d, 0x62, 0xd4, 0x79, 0x46, 0xe3, 0xd8, 0x1b, 0xd3, 0x44, 0xa7, 0x06, 0x49,
+	0x17, 0x9a, 0xc7, 0x2c, 0x0c, 0xd9, 0x97, 0xd3, 0x89, 0x56, 0x9c, 0xc2, 0x64, 0x1d, 0xea, 0x47,
+	0x9c, 0x33, 0x6e, 0x83, 0xdc, 0x50, 0x80, 0x33, 0x80, 0xf6, 0xa1, 0x27, 0xbc, 0xe4, 0xf8, 0x3f,
+	0x83, 0x86, 0x5e, 0x6a, 0x93, 0xd6, 0x32, 0x93, 0xf4, 0x86, 0x9b, 0x50, 0x64, 0x12, 0x2b, 0xa6,
+	0xc4, 0x2f, 0xa0, 0xa3, 0x24, 0xc6, 0x13, 0x16, 0xc5, 0x94, 0x3c, 0x86, 0x96, 0x5a, 0xfb, 0x54,
+	0x51, 0xb6, 0x7b, 0xef, 0x98, 0x42, 0xf5, 0x96, 0x9b, 0x51, 0x65, 0x82, 0xab, 0xa6, 0xe0, 0x4b,
+	0x68, 0x0f, 0x05, 0xa7, 0xde, 0xf5, 0xc1, 0xd5, 0x34, 0x7a, 0x4d, 0x3e, 0x82, 0xfa, 0xc5, 0x84,
+	0xf1, 0xc4, 0x50, 0x43, 0xe6, 0x05, 0xf7, 0xa2, 0x18, 0xb7, 0x5c, 0x45, 0x41, 0x08, 0xd4, 0xd0,
+	0x24, 0xa9, 0xbd, 0xe3, 0xca, 0x75, 0x89, 0x8e, 0x5d, 0xe8, 0x7c, 0x71, 0xc5, 0xfa, 0x71, 0x72,
+	0xc4, 0x07, 0xb0, 0x3c, 0xa4, 0xd2, 0x72, 0x4b, 0x92, 0x69, 0x08, 0xf1, 0x17, 0x1e, 0x1f, 0x53,
+	0xa1, 0xcf, 0xae, 0x21, 0xc7, 0x83, 0x15, 0xcd, 0xaf, 0x4f, 0xff, 0x63, 0x58, 0x51, 0x5b, 0x49,
+	0x96, 0x28, 0x39, 0x79, 0x24, 0x1a, 0x73, 0xc1, 0x5e, 0xd3, 0x28, 0xf1, 0xa4, 0x04, 0x4a, 0x4c,
+	0x5c, 0x85, 0xce, 0xd1, 0xf5, 0x44, 0xcc, 0xb4, 0x89, 0xce, 0xef, 0x2d, 0x58, 0xd1, 0xd9, 0xf2,
+	0x72, 0xe2, 0x7b, 0x42, 0xe6, 0xa4, 0x19, 0xc4, 0x56, 0x16, 0xb1, 0xf2, 0xcc, 0x31, 0xf2, 0xb8,
+	0xb6, 0x30, 0x8f, 0xeb, 0x69, 0x1e, 0x97, 0xd8, 0xf5, 0x07, 0x0b, 0x96, 0xf7, 0x46, 0x22, 0x60,
+	0xd1, 0xb7, 0x18, 0x50, 0xe2, 0x37, 0x4c, 0x5c, 0x97, 0x5e, 0x33, 0x41, 0xfb, 0x87, 0x5a, 0x53,
+	0x0a, 0x9b, 0x46, 0xd7, 0xf2, 0x46, 0x2f, 0x36, 0xe4, 0x8f, 0x16, 0xac, 0x26, 0x75, 0xa3, 0xab,
+	0xad, 0x07, 0x0d, 0x25, 0x0e, 0xfd, 0x5f, 0xcd, 0x57, 0xda, 0x80, 0xb3, 0x11, 0x8d, 0xe3, 0x33,
+	0x2f, 0xf2, 0xc6, 0x94, 0xbb, 0x09, 0x21, 0x79, 0x0c, 0x4d, 0xed, 0x56, 0x74, 0x09, 0x32, 0xbd,
+	0x9b, 0x31, 0x1d, 0x30, 0x4e, 0xf5, 0xae, 0x9b, 0x92, 0x95, 0xd8, 0xf3, 0x5b, 0x58, 0xed, 0x47,
+	0xf1, 0x84, 0x8e, 0x44, 0x62, 0xce, 0xe3, 0x62, 0x93, 0x79, 0x38, 0x57, 0xf8, 0x87, 0x54, 0x78,
+	0x41, 0x98, 0xf5, 0x99, 0xc5, 0xa2, 0xbf, 0xb6, 0x00, 0x4e, 0xd9, 0x38, 0xf1, 0xae, 0xe9, 0x45,
+	0xab, 0xe0, 0xc5, 0x32, 0xcf, 0x3f, 0x80, 0x65, 0xd5, 0x22, 0xa4, 0xe4, 0xa6, 0xab, 0x21, 0x54,
+	0x38, 0x0c, 0xa2, 0x51, 0xe2, 0x73, 0x05, 0x60, 0x25, 0xbd, 0xe0, 0x74, 0xa2, 0x63, 0x24, 0xd7,
+	0x88, 0xbb, 0xf0, 0x82, 0xd0, 0x5e, 0xde, 0xb0, 0x36, 0xeb, 0xae, 0x5c, 0x3b, 0x4f, 0xa0, 0x71,
+	0xca, 0xc6, 0xa7, 0x41, 0x24, 0x59, 0xf0, 0x57, 0x1b, 0x24, 0xd7, 0x25, 0x9d, 0xe3, 0x04, 0x6a,
+	0x83, 0x20, 0x1a, 0xcb, 0xa2, 0x13, 0x9e, 0x98, 0xc6, 0x69, 0xd1, 0x49, 0x48, 0x2a, 0x0a, 0xae,
+	0x93, 0xd4, 0x95, 0xeb, 0x12, 0xbf, 0x70, 0xa8, 0x0d, 0xd8, 0xdb, 0x90, 0x44, 0x36, 0x61, 0xf9,
+	0x84, 0x7a, 0xa1, 0xb8, 0x92, 0x7e, 0x68, 0xf7, 0xee, 0x67, 0x91, 0x52, 0x78, 0x57, 0xef, 0x3b,
+	0xff, 0xb4, 0x12, 0x52, 0x75, 0xe4, 0x1b, 0x75, 0xe4, 0xa6, 0x2b, 0xd7, 0x28, 0xde, 0xa5, 0x9e,
+	0x3f, 0x93, 0x3a, 0x9b, 0xae, 0x02, 0xcc, 0xdc, 0xae, 0xe6, 0x73, 0x7b, 0x1b, 0x1a, 0x2a, 0x07,
+	0x92, 0xec, 0xfb, 0xa0, 0xa8, 0x79, 0x4b, 0xef, 0x1f, 0x45, 0x82, 0xcf, 0xdc, 0x84, 0x1a, 0x45,
+	0x1e, 0x5c, 0xd1, 0xd1, 0x6b, 0xea, 0xeb, 0x28, 0x25, 0x60, 0x77, 0x07, 0x3a, 0x26, 0x0b, 0x56,
+	0xf6, 0x6b, 0x3a, 0xd3, 0xae, 0xc1, 0x25, 0x1a, 0x79, 0xe3, 0x85, 0xd3, 0xc4, 0x31, 0x0a, 0xd8,
+	0xa9, 0x7c, 0x6a, 0x39, 0xff, 0xb6, 0x60, 0x35, 0x5f, 0x29, 0x64, 0x15, 0x2a, 0x69, 0x9e, 0x55,
+	0xfa, 0x87, 0x78, 0xea, 0x73, 0x2f, 0x73, 0x2a, 0xae, 0xcd, 0xb6, 0x52, 0xcd, 0xb7, 0x95, 0x1f,
+	0x40, 0x6b, 0x28, 0x3c, 0x2e, 0x64, 0x1c, 0x94, 0xa1, 0x19, 0x02, 0x03, 0x27, 0xfd, 0x1f, 0xdb,
+	0xcd, 0x8d, 0x2a, 0x06, 0x4e, 0x41, 0x46, 0x40, 0x1b, 0xb9, 0x80, 0xda, 0x32, 0xdf, 0x06, 0x9e,
+	0xb8, 0xb2, 0x5b, 0x4a, 0x8f, 0x06, 0xc9, 0xcf, 0xe7, 0xca, 0x78, 0x6d, 0xae, 0xd8, 0xb2, 0x12,
+	0x76, 0xfe, 0x5a, 0x01, 0xc8, 0xbe, 0xf3, 0xe9, 0x99, 0xac, 0xc2, 0x99, 0xc2, 0xc0, 0x8b, 0x29,
+	0x7e, 0xf2, 0xab, 0xf2, 0x4c, 0x0a, 0xc4, 0xfa, 0xeb, 0xc7, 0xc8, 0x4a, 0xb9, 0xae, 0xa6, 0x14,
+	0x56, 0x7b, 0x07, 0x61, 0x40, 0x23, 0x61, 0xd7, 0x92, 0x3d, 0x05, 0x93, 0x0f, 0x01, 0x06, 0x94,
+	0xf2, 0x17, 0x9c, 0x4d, 0x27, 0xb1, 0xbd, 0x2c, 0x85, 0x1a, 0x18, 0xf4, 0x95, 0xeb, 0x09, 0x7a,
+	0x1a, 0x5c, 0x07, 0x42, 0x1e, 0xdc, 0x72, 0x33, 0x04, 0x06, 0x6d, 0x7f, 0xca, 0x63, 0x61, 0x37,
+	0x65, 0x01, 0x2a, 0x80, 0x6c, 0x40, 0xfb, 0xcc, 0xfb, 0xaa, 0x1f, 0x1d, 0x87, 0xc1, 0xf8, 0x4a,
+	0x48, 0xaf, 0xd4, 0x5d, 0x13, 0x85, 0x14, 0x07, 0xec, 0x7a, 0x82, 0xd1, 0x08, 0x58, 0xa4, 0xaf,
+	0x05, 0x26, 0x0a, 0x4f, 0x3f, 0xc0, 0x2f, 0x6c, 0x5b, 0x55, 0x36, 0xae, 0x9d, 0xff, 0x60, 0x77,
+	0xcd, 0x5d, 0x4e, 0xcc, 0x20, 0x5b, 0xf9, 0x20, 0xab, 0x14, 0xa9, 0xa4, 0x29, 0xb2, 0x01, 0xed,
+	0xe3, 0x20, 0x1a, 0x53, 0x3e, 0xe1, 0x41, 0x24, 0x74, 0x4a, 0x98, 0xa8, 0xa2, 0x51, 0xb5, 0x79,
+	0xa3, 0x7a, 0xb0, 0x6e, 0x80, 0x17, 0x57, 0x9c, 0xc6, 0x57, 0x2c, 0x54, 0xc9, 0x5e, 0x77, 0x17,
+	0xee, 0xa1, 0xf3, 0xf7, 0x83, 0xc8, 0x3f, 0x61, 0xb1, 0x90, 0x6d, 0xaa, 0xe5, 0xa6, 0x30, 0xee,
+	0x1d, 0x72, 0x2f, 0x88, 0x82, 0x68, 0x2c, 0x7d, 0xdb, 0x74, 0x53, 0xd8, 0xf9, 0x5b, 0x25, 0xfd,
+	0xb6, 0xaa, 0xca, 0x79, 0xc3, 0xa4, 0x37, 0x52, 0xa7, 0x96, 0x4f, 0x9d, 0x7c, 0x0a, 0xd4, 0xe7,
+	0x52, 0x00, 0xdb, 0xb1, 0xf0, 0x04, 0xd5, 0xe6, 0x2b, 0x00, 0xb1, 0x7b, 0xbe, 0x4f, 0x7d, 0x5d,
+	0x0d, 0x0a, 0xc0, 0x13, 0x9d, 0x7a, 0xb1, 0xc0, 0x5e, 0x2a, 0x73, 0xa2, 0xe5, 0xa6, 0xb0, 0x4c,
+	0xc3, 0x7c, 0x4e, 0xa4, 0x70, 0xce, 0x13, 0x90, 0xf7, 0x84, 0xd1, 0x07, 0xdb, 0xb7, 0xf4, 0xc1,
+	0x7f, 0x54, 0xd2, 0xaf, 0x9b, 0xf4, 0x96, 0xaf, 0x33, 0xab, 0xd2, 0xf7, 0x53, 0x6f, 0xb5, 0x0d,
+	0x6f, 0x11, 0xa8, 0x9d, 0x31, 0x9f, 0xda, 0x0f, 0x15, 0x0e, 0xd7, 0xa6, 0x07, 0xdf, 0xcd, 0x7b,
+	0x10, 0x53, 0x12, 0xab, 0xbc, 0xa3, 0xa8, 0x71, 0x6d, 0x16, 0xff, 0x7a, 0xbe, 0xf8, 0xb3, 0x76,
+	0xb1, 0x9a, 0x6b, 0x17, 0xf2, 0x43, 0x19, 0x63, 0xb7, 0x89, 0xed, 0x7b, 0xca, 0x0b, 0x09, 0x8c,
+	0x3e, 0x3d, 0x96, 0x6d, 0xd7, 0x56, 0xe5, 0x24, 0x01, 0xec, 0x95, 0x83, 0xc0, 0xb7, 0xef, 0x4b,
+	0x1c, 0x2e, 0xf3, 0x0d, 0x6c, 0xad, 0xd8, 0xc0, 0xf0, 0x26, 0xee, 0x05, 0xa1, 0xdc, 0x24, 0xfa,
+	0x26, 0xae, 0x61, 0x15, 0x9f, 0x68, 0x3c, 0xc5, 0xae, 0xff, 0x5e, 0x12, 0x1f, 0x05, 0x1b, 0x8d,
+	0xef, 0x1d, 0xb3, 0xf1, 0x39, 0xdf, 0x54, 0xa0, 0x6d, 0x5c, 0x3a, 0x4a, 0x1b, 0xd3, 0xe2, 0xb7,
+	0x48, 0xe2, 0xe3, 0xaa, 0xe1, 0xe3, 0x7c, 0xc6, 0x35, 0xe6, 0x32, 0xae, 0x0b, 0xcd, 0x81, 0xc7,
+	0x69, 0x24, 0xb2, 0x2b, 0x59, 0x02, 0x1b, 0x56, 0xd6, 0x72, 0xed, 0xf9, 0x39, 0x7e, 0xce, 0x04,
+	0x0f, 0x46, 0xaa, 0x6f, 0xb7, 0x7b, 0xce, 0xc2, 0x2b, 0xd3, 0x96, 0x26, 0xd2, 0x5f, 0x2e, 0x0d,
+	0x19, 0x39, 0xd6, 0xfa, 0xf6, 0x1c, 0xc3, 0x2f, 0x99, 0x29, 0xe2, 0xb6, 0x2f, 0x59, 0xd5, 0xfc,
+	0x92, 0xfd, 0xdd, 0x82, 0xc6, 0x77, 0xbc, 0xde, 0x23, 0xfe, 0x8c, 0x8a, 0x2b, 0xe6, 0x6b, 0x4f,
+	0x6a, 0x08, 0xb5, 0xe1, 0xa3, 0xe2, 0xb1, 0xdd, 0x53, 0x75, 0x28, 0x81, 0xec, 0x85, 0xb2, 0x7d,
+	0xeb, 0x0b, 0xe5, 0x27, 0x50, 0x1f, 0x84, 0xcc, 0xf3, 0xed, 0xe7, 0xc5, 0x57, 0xd7, 0xc0, 0x9b,
+	0xe1, 0x86, 0xab, 0xf6, 0x51, 0xd3, 0x01, 0xf3, 0xe9, 0xc8, 0x3e, 0x56, 0x9a, 0x24, 0xe0, 0xfc,
+	0xcb, 0x32, 0x1e, 0x59, 0x68, 0xa5, 0x4b, 0xe3, 0x69, 0x28, 0xb4, 0x39, 0x1a, 0xc2, 0xde, 0x2a,
+	0xe3, 0x34, 0x14, 0x1c, 0x4b, 0xfc, 0x52, 0xf5, 0x56, 0x03, 0x85, 0x31, 0x3f, 0xf1, 0x7c, 0x89,
+	0xb1, 0x47, 0xaa, 0x03, 0x24, 0xf0, 0xff, 0xe5, 0x34, 0xf8, 0xb6, 0xe0, 0xdc, 0xde, 0xd3, 0x6f,
+	0x0b, 0xce, 0x4b, 0xce, 0x37, 0x84, 0x56, 0xaa, 0xe4, 0x6d, 0x05, 0xcd, 0xf9, 0x7a, 0x05, 0x1a,
+	0xda, 0x1e, 0xf2, 0x0c, 0x96, 0x8f, 0x03, 0x1a, 0xfa, 0xb1, 0xdd, 0x2b, 0x5e, 0xb6, 0x34, 0xc9,
+	0x96, 0xda, 0x57, 0x29, 0xab, 0x89, 0xc9, 0x23, 0xa8, 0x7d, 0x3e, 0xfc, 0xcd, 0xb9, 0xbd, 0x2d,
+	0x99, 0xde, 0x9f, 0x67, 0xc2, 0x5d, 0xc5, 0x22, 0x09, 0xc9, 0x1e, 0xc0, 0x05, 0xfd, 0x4a, 0x68,
+	0x5d, 0xcf, 0x25, 0xdb, 0x0f, 0xe7, 0xd9, 0x32, 0x1a, 0xc5, 0x6c, 0x30, 0xa1, 0x88, 0x7d, 0xc6,
+	0x42, 0x2d, 0x62, 0xb7, 0x4c, 0x44, 0x46, 0xa3, 0x45, 0x64, 0x08, 0x29, 0x62, 0x26, 0xa8, 0x16,
+	0xf1, 0x59, 0xa9, 0x88, 0x94, 0x26, 0x11, 0x91, 0x22, 0xc8, 0x2e, 0xb4, 0xfa, 0x51, 0x72, 0x8e,
+	0x7d, 0x29, 0x61, 0x63, 0x5e, 0x42, 0x4a, 0xa2, 0x04, 0x64, 0x2c, 0xe4, 0x10, 0xda, 0xfd, 0x48,
+	0x7c, 0xf2, 0x54, 0x4b, 0x38, 0x2c, 0x76, 0x0b, 0x43, 0xc2, 0x27, 0x4f, 0x4d, 0x19, 0x26, 0x1b,
+	0x1e, 0xe4, 0x65, 0x90, 0x9a, 0x71, 0x5c, 0x76, 0x90, 0x8c, 0x46, 0x1f, 0x24, 0x43, 0x90, 0x17,
+	0xd0, 0x79, 0x19, 0x64, 0x22, 0xed, 0x13, 0x29, 0xe4, 0x47, 0x8b, 0x85, 0xe4, 0x4d, 0xc9, 0x31,
+	0xa2, 0xa0, 0x43, 0x36, 0xbd, 0x0c, 0x13, 0xb7, 0x7e, 0x5e, 0x26, 0xc8, 0xa4, 0xd2, 0x82, 0x4c,
+	0x14, 0xba, 0xe6, 0x38, 0x64, 0x5e, 0x72, 0xaa, 0xd3, 0x32, 0xd7, 0x18, 0x44, 0xda, 0x35, 0x06,
+	0x06, 0x53, 0x53, 0xce, 0x3c, 0x06, 0x65, 0xa9, 0x89, 0xbb, 0x3a, 0x35, 0x71, 0x49, 0x3e, 0x85,
+	0xc6, 0x51, 0x34, 0x62, 0x78, 0x9b, 0x70, 0x25, 0xcf, 0x87, 0xf3, 0x3c, 0x9a, 0x40, 0xf7, 0x6d,
+	0x0d, 0x75, 0xcf, 0xa1, 0xad, 0x94, 0x96, 0x35, 0xe3, 0x8f, 0xcc, 0x66, 0x9c, 0x6b, 0x1d, 0xf1,
+	0xf4, 0x52, 0xb1, 0x1a, 0x1d, 0xba, 0xbb, 0x0d, 0xad, 0xb4, 0x6e, 0x6e, 0x6b, 0xed, 0x1d, 0x93,
+	0xf1, 0x97, 0x70, 0xaf, 0x50, 0x39, 0x77, 0x79, 0xe3, 0x20, 0x7b, 0xa1, 0x6a, 0x6e, 0x63, 0x6f,
+	0x16, 0xd9, 0xf3, 0x15, 0x73, 0x27, 0xe3, 0x9f, 0xe3, 0x98, 0xe0, 0x2e, 0xb6, 0xd7, 0x4d, 0xee,
+	0x5d, 0xb8, 0x5f, 0x2c, 0x95, 0xbb, 0x7c, 0x15, 0xd1, 0xf8, 0x42, 0x95, 0xdc, 0xc6, 0xbe, 0x62,
+	0xb2, 0xff, 0x0a, 0xd6, 0xe6, 0xea, 0xe3, 0x36, 0x01, 0xb5, 0x82, 0x80, 0xb9, 0xba, 0xb8, 0x4d,
+	0x80, 0x55, 0x70, 0x40, 0xb1, 0x20, 0x6e, 0xe3, 0xaf, 0x98, 0xfc, 0x67, 0xd0, 0x4a, 0x2b, 0x62,
+	0x01, 0xe3, 0x4f, 0xf3, 0x29, 0xbc, 0xbe, 0xa5, 0xe6, 0xca, 0x5b, 0xc9, 0x5c, 0x79, 0x6b, 0x2f,
+	0x9a, 0x99, 0xe2, 0x76, 0xa0, 0x63, 0x16, 0xcb, 0x5d, 0x32, 0xc1, 0xf9, 0x00, 0x5a, 0x69, 0x5d,
+	0x20, 0xe3, 0x70, 0x7a, 0x29, 0xc7, 0x56, 0x2d, 0x17, 0x97, 0xbd, 0x3f, 0xd7, 0xa1, 0x7e, 0xe0,
+	0x5d, 0x7a, 0x21, 0x39, 0x80, 0x7b, 0x2e, 0x1d, 0x07, 0xb1, 0xa0, 0x3c, 0xb9, 0x17, 0xbe, 0xbf,
+	0x70, 0x5c, 0xad, 0xae, 0x3b, 0xdd, 0xdc, 0x30, 0x57, 0x8e, 0xa2, 0x9c, 0x25, 0xb2, 0x0f, 0x44,
+	0xcd, 0x0d, 0x95, 0x28, 0xee, 0xc9, 0x11, 0xde, 0xfc, 0x44, 0x4a, 0x11, 0x2d, 0x96, 0xb1, 0xad,
+	0x9a, 0x0d, 0x31, 0x26, 0x64, 0xc6, 0x54, 0xb9, 0xfb, 0xa0, 0x88, 0x56, 0xc3, 0x51, 0x67, 0x89,
+	0x7c, 0x06, 0x80, 0x18, 0x35, 0xd7, 0x35, 0xd9, 0x8d, 0x49, 0x6f, 0x77, 0x31, 0xda, 0x59, 0xda,
+	0xb4, 0x3e, 0xb6, 0xc8, 0x0e, 0xd4, 0xe5, 0xc4, 0x95, 0x18, 0x4a, 0xcc, 0x11, 0x6e, 0xf7, 0xe1,
+	0x1c, 0x3e, 0xd5, 0xbe, 0x0d, 0x8d, 0xe4, 0x0d, 0x6b, 0xdc, 0x35, 0xd5, 0x10, 0xb3, 0x6b, 0x0e,
+	0xe3, 0x73, 0xd3, 0x44, 0x67, 0x09, 0x1b, 0xda, 0x5e, 0x88, 0x53, 0x9d, 0x55, 0xa3, 0x47, 0x06,
+	0xd1, 0xb8, 0x6b, 0xc2, 0x2c, 0x1a, 0x3b, 0x4b, 0xe4, 0x19, 0x74, 0x8e, 0x6e, 0x82, 0x91, 0x48,
+	0x02, 0x34, 0xaf, 0x68, 0xa1, 0x47, 0x9f, 0x41, 0x47, 0xbe, 0xbd, 0xee, 0xc8, 0xb6, 0x0d, 0xab,
+	0x2f, 0x23, 0xff, 0x3b, 0x30, 0xee, 0xa6, 0x43, 0xca, 0x72, 0x46, 0xc3, 0x23, 0xf9, 0x81, 0xa6,
+	0xb3, 0xd4, 0xfb, 0x4b, 0x05, 0x96, 0xd5, 0xac, 0x91, 0x3c, 0x85, 0xce, 0x39, 0x13, 0xc1, 0xab,
+	0x99, 0x62, 0x5b, 0x20, 0x68, 0x0e, 0xf3, 0x26, 0xb1, 0x78, 0x02, 0xb5, 0x53, 0x36, 0x8e, 0x89,
+	0xf1, 0x47, 0x4d, 0x36, 0x12, 0x35, 0x0f, 0xab, 0x07, 0x92, 0xce, 0xd2, 0xc7, 0xd6, 0x5b, 0x49,
+	0xfa, 0xff, 0x3d, 0x09, 0x7a, 0xdf, 0x54, 0xa1, 0x71, 0xc0, 0x22, 0xc1, 0x59, 0x88, 0x91, 0x95,
+	0xcf, 0xc4, 0xbb, 0x47, 0x56, 0x3f, 0x51, 0xef, 0xc8, 0xf8, 0x14, 0xda, 0xbf, 0x0e, 0xc2, 0xf0,
+	0xce, 0xea, 0xbe, 0xbf, 0xe1, 0x20, 0xbf, 0x00, 0x18, 0x0a, 0x36, 0xd1, 0x93, 0x39, 0xa3, 0x71,
+	0x98, 0x7f, 0xac, 0x2c, 0xd4, 0x72, 0xb9, 0x2c, 0x1b, 0xfe, 0x93, 0xff, 0x0e, 0x00, 0x09, 0xdc,
+	0xca, 0xb6, 0x72, 0x1c, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1650,9 +2262,14 @@ type CabalClient interface {
 	RegisterService(ctx context.Context, in *NewServiceRequest, opts ...grpc.CallOption) (*Receipt, error)
 	UpdateRegistration(ctx context.Context, in *ServiceUpdate, opts ...grpc.CallOption) (*Receipt, error)
 	Data(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (*DataResponse, error)
+	DataStream(ctx context.Context, opts ...grpc.CallOption) (Cabal_DataStreamClient, error)
 	WhoIs(ctx context.Context, in *WhoIsRequest, opts ...grpc.CallOption) (*WhoIsResponse, error)
 	Summary(ctx context.Context, in *Action, opts ...grpc.CallOption) (*SummaryReceipt, error)
 	Alive(ctx context.Context, in *Ping, opts ...grpc.CallOption) (*Pong, error)
+	EvictService(ctx context.Context, in *Action, opts ...grpc.CallOption) (*Receipt, error)
+	DrainService(ctx context.Context, in *Action, opts ...grpc.CallOption) (*Receipt, error)
+	UndrainService(ctx context.Context, in *Action, opts ...grpc.CallOption) (*Receipt, error)
+	InspectService(ctx context.Context, in *Action, opts ...grpc.CallOption) (*InspectReceipt, error)
 }
 
 type cabalClient struct {
@@ -1690,6 +2307,37 @@ func (c *cabalClient) Data(ctx context.Context, in *DataRequest, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *cabalClient) DataStream(ctx context.Context, opts ...grpc.CallOption) (Cabal_DataStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Cabal_serviceDesc.Streams[0], "/intrigue.Cabal/DataStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cabalDataStreamClient{stream}
+	return x, nil
+}
+
+type Cabal_DataStreamClient interface {
+	Send(*StreamChunk) error
+	Recv() (*StreamChunk, error)
+	grpc.ClientStream
+}
+
+type cabalDataStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *cabalDataStreamClient) Send(m *StreamChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *cabalDataStreamClient) Recv() (*StreamChunk, error) {
+	m := new(StreamChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *cabalClient) WhoIs(ctx context.Context, in *WhoIsRequest, opts ...grpc.CallOption) (*WhoIsResponse, error) {
 	out := new(WhoIsResponse)
 	err := c.cc.Invoke(ctx, "/intrigue.Cabal/WhoIs", in, out, opts...)
@@ -1717,14 +2365,55 @@ func (c *cabalClient) Alive(ctx context.Context, in *Ping, opts ...grpc.CallOpti
 	return out, nil
 }
 
+func (c *cabalClient) EvictService(ctx context.Context, in *Action, opts ...grpc.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := c.cc.Invoke(ctx, "/intrigue.Cabal/EvictService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cabalClient) DrainService(ctx context.Context, in *Action, opts ...grpc.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := c.cc.Invoke(ctx, "/intrigue.Cabal/DrainService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cabalClient) UndrainService(ctx context.Context, in *Action, opts ...grpc.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := c.cc.Invoke(ctx, "/intrigue.Cabal/UndrainService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cabalClient) InspectService(ctx context.Context, in *Action, opts ...grpc.CallOption) (*InspectReceipt, error) {
+	out := new(InspectReceipt)
+	err := c.cc.Invoke(ctx, "/intrigue.Cabal/InspectService", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CabalServer is the server API for Cabal service.
 type CabalServer interface {
 	RegisterService(context.Context, *NewServiceRequest) (*Receipt, error)
 	UpdateRegistration(context.Context, *ServiceUpdate) (*Receipt, error)
 	Data(context.Context, *DataRequest) (*DataResponse, error)
+	DataStream(Cabal_DataStreamServer) error
 	WhoIs(context.Context, *WhoIsRequest) (*WhoIsResponse, error)
 	Summary(context.Context, *Action) (*SummaryReceipt, error)
 	Alive(context.Context, *Ping) (*Pong, error)
+	EvictService(context.Context, *Action) (*Receipt, error)
+	DrainService(context.Context, *Action) (*Receipt, error)
+	UndrainService(context.Context, *Action) (*Receipt, error)
+	InspectService(context.Context, *Action) (*InspectReceipt, error)
 }
 
 func RegisterCabalServer(s *grpc.Server, srv CabalServer) {
@@ -1785,6 +2474,32 @@ func _Cabal_Data_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Cabal_DataStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CabalServer).DataStream(&cabalDataStreamServer{stream})
+}
+
+type Cabal_DataStreamServer interface {
+	Send(*StreamChunk) error
+	Recv() (*StreamChunk, error)
+	grpc.ServerStream
+}
+
+type cabalDataStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *cabalDataStreamServer) Send(m *StreamChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *cabalDataStreamServer) Recv() (*StreamChunk, error) {
+	m := new(StreamChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _Cabal_WhoIs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(WhoIsRequest)
 	if err := dec(in); err != nil {
@@ -1839,6 +2554,78 @@ func _Cabal_Alive_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Cabal_EvictService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Action)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CabalServer).EvictService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/intrigue.Cabal/EvictService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CabalServer).EvictService(ctx, req.(*Action))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cabal_DrainService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Action)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CabalServer).DrainService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/intrigue.Cabal/DrainService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CabalServer).DrainService(ctx, req.(*Action))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cabal_UndrainService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Action)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CabalServer).UndrainService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/intrigue.Cabal/UndrainService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CabalServer).UndrainService(ctx, req.(*Action))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cabal_InspectService_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Action)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CabalServer).InspectService(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/intrigue.Cabal/InspectService",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CabalServer).InspectService(ctx, req.(*Action))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Cabal_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "intrigue.Cabal",
 	HandlerType: (*CabalServer)(nil),
@@ -1867,8 +2654,31 @@ var _Cabal_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Alive",
 			Handler:    _Cabal_Alive_Handler,
 		},
+		{
+			MethodName: "EvictService",
+			Handler:    _Cabal_EvictService_Handler,
+		},
+		{
+			MethodName: "DrainService",
+			Handler:    _Cabal_DrainService_Handler,
+		},
+		{
+			MethodName: "UndrainService",
+			Handler:    _Cabal_UndrainService_Handler,
+		},
+		{
+			MethodName: "InspectService",
+			Handler:    _Cabal_InspectService_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DataStream",
+			Handler:       _Cabal_DataStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "intrigue.proto",
 }
 
@@ -1878,6 +2688,7 @@ var _Cabal_serviceDesc = grpc.ServiceDesc{
 type RemoteClient interface {
 	NotifyAction(ctx context.Context, in *Action, opts ...grpc.CallOption) (*Action, error)
 	Summary(ctx context.Context, in *Action, opts ...grpc.CallOption) (*SummaryReceipt, error)
+	Logs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (Remote_LogsClient, error)
 	UpdateRegistration(ctx context.Context, in *ServiceUpdate, opts ...grpc.CallOption) (*Receipt, error)
 	Alive(ctx context.Context, in *Ping, opts ...grpc.CallOption) (*Pong, error)
 }
@@ -1908,6 +2719,38 @@ func (c *remoteClient) Summary(ctx context.Context, in *Action, opts ...grpc.Cal
 	return out, nil
 }
 
+func (c *remoteClient) Logs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (Remote_LogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Remote_serviceDesc.Streams[0], "/intrigue.Remote/Logs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &remoteLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Remote_LogsClient interface {
+	Recv() (*LogLine, error)
+	grpc.ClientStream
+}
+
+type remoteLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *remoteLogsClient) Recv() (*LogLine, error) {
+	m := new(LogLine)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *remoteClient) UpdateRegistration(ctx context.Context, in *ServiceUpdate, opts ...grpc.CallOption) (*Receipt, error) {
 	out := new(Receipt)
 	err := c.cc.Invoke(ctx, "/intrigue.Remote/UpdateRegistration", in, out, opts...)
@@ -1930,6 +2773,7 @@ func (c *remoteClient) Alive(ctx context.Context, in *Ping, opts ...grpc.CallOpt
 type RemoteServer interface {
 	NotifyAction(context.Context, *Action) (*Action, error)
 	Summary(context.Context, *Action) (*SummaryReceipt, error)
+	Logs(*LogRequest, Remote_LogsServer) error
 	UpdateRegistration(context.Context, *ServiceUpdate) (*Receipt, error)
 	Alive(context.Context, *Ping) (*Pong, error)
 }
@@ -1974,6 +2818,27 @@ func _Remote_Summary_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Remote_Logs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RemoteServer).Logs(m, &remoteLogsServer{stream})
+}
+
+type Remote_LogsServer interface {
+	Send(*LogLine) error
+	grpc.ServerStream
+}
+
+type remoteLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *remoteLogsServer) Send(m *LogLine) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Remote_UpdateRegistration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ServiceUpdate)
 	if err := dec(in); err != nil {
@@ -2031,7 +2896,13 @@ var _Remote_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Remote_Alive_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Logs",
+			Handler:       _Remote_Logs_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "intrigue.proto",
 }
 
@@ -2043,6 +2914,7 @@ type ControlClient interface {
 	RestartService(ctx context.Context, in *Action, opts ...grpc.CallOption) (*Receipt, error)
 	KillService(ctx context.Context, in *Action, opts ...grpc.CallOption) (*Receipt, error)
 	Summary(ctx context.Context, in *Action, opts ...grpc.CallOption) (*SummaryReceipt, error)
+	Logs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (Control_LogsClient, error)
 	UpdateRegistration(ctx context.Context, in *ServiceUpdate, opts ...grpc.CallOption) (*Receipt, error)
 	Alive(ctx context.Context, in *Ping, opts ...grpc.CallOption) (*Pong, error)
 	StopServer(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*Receipt, error)
@@ -2092,6 +2964,38 @@ func (c *controlClient) Summary(ctx context.Context, in *Action, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *controlClient) Logs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (Control_LogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[0], "/intrigue.Control/Logs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_LogsClient interface {
+	Recv() (*LogLine, error)
+	grpc.ClientStream
+}
+
+type controlLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlLogsClient) Recv() (*LogLine, error) {
+	m := new(LogLine)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *controlClient) UpdateRegistration(ctx context.Context, in *ServiceUpdate, opts ...grpc.CallOption) (*Receipt, error) {
 	out := new(Receipt)
 	err := c.cc.Invoke(ctx, "/intrigue.Control/UpdateRegistration", in, out, opts...)
@@ -2125,6 +3029,7 @@ type ControlServer interface {
 	RestartService(context.Context, *Action) (*Receipt, error)
 	KillService(context.Context, *Action) (*Receipt, error)
 	Summary(context.Context, *Action) (*SummaryReceipt, error)
+	Logs(*LogRequest, Control_LogsServer) error
 	UpdateRegistration(context.Context, *ServiceUpdate) (*Receipt, error)
 	Alive(context.Context, *Ping) (*Pong, error)
 	StopServer(context.Context, *EmptyRequest) (*Receipt, error)
@@ -2206,6 +3111,27 @@ func _Control_Summary_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Control_Logs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).Logs(m, &controlLogsServer{stream})
+}
+
+type Control_LogsServer interface {
+	Send(*LogLine) error
+	grpc.ServerStream
+}
+
+type controlLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlLogsServer) Send(m *LogLine) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Control_UpdateRegistration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ServiceUpdate)
 	if err := dec(in); err != nil {
@@ -2293,6 +3219,12 @@ var _Control_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Control_StopServer_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Logs",
+			Handler:       _Control_Logs_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "intrigue.proto",
 }
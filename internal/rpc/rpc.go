@@ -2,8 +2,14 @@ package rpc
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,6 +19,14 @@ import (
 	"google.golang.org/grpc/reflection"
 )
 
+// UnixPrefix marks an address as the path of a unix domain socket, such as
+// unix:///var/run/gmbh/core.sock, which is faster than tcp when everything runs on one host
+const UnixPrefix = "unix://"
+
+// SocketMode is the permission of the socket files that servers listen on. Services that run
+// as other users need to share the group of the socket to connect to it.
+const SocketMode os.FileMode = 0660
+
 // Connection holds data related to a grpc connection
 type Connection struct {
 	Server    *grpc.Server
@@ -24,6 +38,13 @@ type Connection struct {
 	Connected bool
 	mu        *sync.Mutex
 	Errors    []error
+
+	// Options are passed to the grpc server when it is created in Connect
+	Options []grpc.ServerOption
+
+	// BindAddress is listened on instead of Address when it is set, for when the server is
+	// reached at a different address than the one it listens on
+	BindAddress string
 }
 
 // NewCabalConnection returns a new connection object
@@ -73,7 +94,11 @@ func (c *Connection) Connect() error {
 		return errors.New("connection.connect.noAddress")
 	}
 
-	list, err := net.Listen("tcp", c.Address)
+	listen := c.Address
+	if c.BindAddress != "" {
+		listen = c.BindAddress
+	}
+	list, err := Listen(listen)
 	if err != nil {
 		return errors.New("connection.connect.listener=(" + err.Error() + ")")
 	}
@@ -85,7 +110,7 @@ func (c *Connection) Connect() error {
 		// 	Timeout: time.Second * 15,
 		// }
 		// a := grpc.KeepaliveParams(parms)
-		c.Server = grpc.NewServer()
+		c.Server = grpc.NewServer(c.Options...)
 
 		if c.ctype == "cabal" {
 			intrigue.RegisterCabalServer(c.Server, c.Cabal)
@@ -117,6 +142,16 @@ func (c *Connection) Disconnect() {
 		c.Server.Stop()
 	}
 	c.Connected = false
+
+	// the listener removes its socket when it is closed, this covers a server that never
+	// got to serve on it
+	listen := c.Address
+	if c.BindAddress != "" {
+		listen = c.BindAddress
+	}
+	if IsUnix(listen) {
+		os.Remove(SocketPath(listen))
+	}
 }
 
 // IsConnected to grpc server
@@ -124,9 +159,10 @@ func (c *Connection) IsConnected() bool {
 	return c.Connected
 }
 
-// GetCabalRequest returns a cabal client to make requests through at address and with timeout
-func GetCabalRequest(address string, timeout time.Duration) (intrigue.CabalClient, context.Context, context.CancelFunc, error) {
-	con, err := grpc.Dial(address, grpc.WithInsecure())
+// GetCabalRequest returns a cabal client to make requests through at address and with timeout.
+// Any opts are added to the dial options.
+func GetCabalRequest(address string, timeout time.Duration, opts ...grpc.DialOption) (intrigue.CabalClient, context.Context, context.CancelFunc, error) {
+	con, err := dial(address, opts...)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -134,9 +170,19 @@ func GetCabalRequest(address string, timeout time.Duration) (intrigue.CabalClien
 	return intrigue.NewCabalClient(con), ctx, can, nil
 }
 
+// DialCabal returns a cabal client at address for streams, which cannot use the timeout of
+// GetCabalRequest. The connection must be closed once the stream is done.
+func DialCabal(address string, opts ...grpc.DialOption) (intrigue.CabalClient, *grpc.ClientConn, error) {
+	con, err := dial(address, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return intrigue.NewCabalClient(con), con, nil
+}
+
 // GetControlRequest returns a control client to make requests through at address and with timeout
 func GetControlRequest(address string, timeout time.Duration) (intrigue.ControlClient, context.Context, context.CancelFunc, error) {
-	con, err := grpc.Dial(address, grpc.WithInsecure())
+	con, err := dial(address)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -144,12 +190,109 @@ func GetControlRequest(address string, timeout time.Duration) (intrigue.ControlC
 	return intrigue.NewControlClient(con), ctx, can, nil
 }
 
+// DialControl returns a control client at address for streams. The connection must be closed
+// once the stream is done.
+func DialControl(address string) (intrigue.ControlClient, *grpc.ClientConn, error) {
+	con, err := dial(address)
+	if err != nil {
+		return nil, nil, err
+	}
+	return intrigue.NewControlClient(con), con, nil
+}
+
 // GetRemoteRequest returns a remote client to make requests through at address and with timeout
 func GetRemoteRequest(address string, timeout time.Duration) (intrigue.RemoteClient, context.Context, context.CancelFunc, error) {
-	con, err := grpc.Dial(address, grpc.WithInsecure())
+	con, err := dial(address)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	ctx, can := context.WithTimeout(context.Background(), timeout)
 	return intrigue.NewRemoteClient(con), ctx, can, nil
 }
+
+// DialRemote returns a remote client at address for streams. The connection must be closed
+// once the stream is done.
+func DialRemote(address string) (intrigue.RemoteClient, *grpc.ClientConn, error) {
+	con, err := dial(address)
+	if err != nil {
+		return nil, nil, err
+	}
+	return intrigue.NewRemoteClient(con), con, nil
+}
+
+// IsUnix returns true if address is a unix domain socket
+func IsUnix(address string) bool {
+	return strings.HasPrefix(address, UnixPrefix)
+}
+
+// SocketPath returns the path of the socket file of a unix address
+func SocketPath(address string) string {
+	return strings.TrimPrefix(address, UnixPrefix)
+}
+
+// Listen on address, which is either host:port or a unix socket. A socket file left behind
+// by a server that did not shut down cleanly is removed first, while one that is still being
+// served on is reported as in use.
+func Listen(address string) (net.Listener, error) {
+	if !IsUnix(address) {
+		return net.Listen("tcp", address)
+	}
+
+	path := SocketPath(address)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, errors.New(path + " exists and is not a socket")
+		}
+		if con, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			con.Close()
+			return nil, errors.New(path + " is in use")
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	list, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, SocketMode); err != nil {
+		list.Close()
+		return nil, err
+	}
+	return list, nil
+}
+
+// dial address with opts, connecting to unix sockets directly as the resolvers of grpc only
+// know of host:port
+func dial(address string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{grpc.WithInsecure()}, opts...)
+	if IsUnix(address) {
+		path := SocketPath(address)
+		opts = append(opts, grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		}))
+	}
+	return grpc.Dial(address, opts...)
+}
+
+// SignSender returns the token that vouches for sender when it makes requests to the
+// service that was issued fingerprint. Only core and the receiving service know the
+// fingerprint so only they can create or check a token.
+func SignSender(fingerprint, sender string) string {
+	mac := hmac.New(sha256.New, []byte(fingerprint))
+	mac.Write([]byte(sender))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySender returns true if token was created by SignSender with fingerprint and sender
+func VerifySender(fingerprint, sender, token string) bool {
+	if fingerprint == "" || token == "" {
+		return false
+	}
+	return hmac.Equal([]byte(SignSender(fingerprint, sender)), []byte(token))
+}
@@ -0,0 +1,92 @@
+package gmbh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/vmihailenco/msgpack"
+)
+
+const (
+	// JSONCodec is the default codec understood by every gmbh client
+	JSONCodec = "json"
+
+	// MsgPackCodec encodes the payload values with MessagePack
+	MsgPackCodec = "msgpack"
+)
+
+// errCodecUnsupported is returned to the sender when a request was encoded with a codec that
+// is not registered with the receiving service
+const errCodecUnsupported = "codec.unsupported"
+
+// Codec marshals the values of a payload. The name of the codec is sent with each request and
+// response so that the receiver knows how to read the values, which means that the sender and
+// receiver must both have registered a codec with the same name.
+type Codec interface {
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	codecs = map[string]Codec{
+		JSONCodec:    jsonCodec{},
+		MsgPackCodec: msgpackCodec{},
+	}
+	codecsMu = &sync.RWMutex{}
+)
+
+// RegisterCodec makes a codec available to NewPayloadWithCodec and to the requests received by
+// this service. A codec with the same name as one already registered replaces it.
+func RegisterCodec(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[c.Name()] = c
+}
+
+// getCodec returns the codec registered with name, the empty name is JSON
+func getCodec(name string) (Codec, error) {
+	if name == "" {
+		name = JSONCodec
+	}
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	c, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %s", errCodecUnsupported, name)
+	}
+	return c, nil
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return JSONCodec
+}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// msgpackCodec uses the json struct tags so that the same types can be sent with either codec
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string {
+	return MsgPackCodec
+}
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := msgpack.NewEncoder(&buf).UseJSONTag(true).Encode(v)
+	return buf.Bytes(), err
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return msgpack.NewDecoder(bytes.NewReader(data)).UseJSONTag(true).Decode(v)
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/gmbh-micro/rpc"
+	"github.com/gmbh-micro/rpc/address"
+	"google.golang.org/grpc"
 )
 
 func (g *Client) connect() {
@@ -40,7 +42,10 @@ func (g *Client) connect() {
 
 	g.mu.Lock()
 	g.reg = reg
+	g.draining = reg.draining
 	g.con = rpc.NewCabalConnection(reg.address, &_server{})
+	g.con.Options = []grpc.ServerOption{grpc.StatsHandler(g.compression)}
+	g.con.BindAddress = address.Bind(reg.bindHost, reg.address)
 	g.state = Connected
 
 	g.mu.Unlock()
@@ -1,6 +1,7 @@
 package gmbh
 
 import (
+	"context"
 	"errors"
 
 	"github.com/gmbh-micro/rpc/intrigue"
@@ -23,26 +24,40 @@ func (g *Client) Route(route string, handler HandlerFunc) {
 	g.registeredFunctions[route] = handler
 }
 
-// MakeRequest is the default method for making data requests through gmbh
+// MakeRequest is the default method for making data requests through gmbh. If the request
+// fails, GetError of the returned Responder holds the error code from gmbh.
 func (g *Client) MakeRequest(target, method string, data *Payload) (Responder, error) {
 	resp, err := makeDataRequest(target, method, data)
 	if err != nil {
-		return Responder{}, errors.New("could not complete request: " + err.Error())
+		if resp.err == "" {
+			resp.err = err.Error()
+		}
+		return Responder{err: resp.err}, errors.New("could not complete request: " + err.Error())
 	}
 	return resp, nil
 }
 
-func handleDataRequest(req intrigue.Request) (*intrigue.Responder, error) {
+func handleDataRequest(ctx context.Context, req intrigue.Request) (*intrigue.Responder, error) {
 
-	var request Request
-	request = requestFromProto(&req)
-	responder := Responder{}
+	request, err := requestFromProto(&req)
+	if err != nil {
+		print("could not read request; codec=%s; err=%s", req.GetCodec(), err.Error())
+		return nil, err
+	}
+	responder := Responder{codec: req.GetCodec()}
 
 	handler, ok := g.registeredFunctions[request.transport.Method]
 	if !ok {
 		print("could not find hander=%s", request.transport.Method)
 		responder.err = "could not find method in service map"
 	} else {
+		pool := g.getPool(request.transport.Method)
+		err := pool.acquire(ctx)
+		if err != nil {
+			print("no handler available for %s; err=%s", request.transport.Method, err.Error())
+			return nil, err
+		}
+		defer pool.release()
 		handler(request, &responder)
 	}
 	protoResponder := responder.proto()
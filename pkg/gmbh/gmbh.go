@@ -19,6 +19,9 @@ import (
 	"github.com/gmbh-micro/notify"
 	"github.com/gmbh-micro/rpc"
 	"github.com/gmbh-micro/rpc/intrigue"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 )
 
 // registration contains data that is received from core at registration time
@@ -32,11 +35,21 @@ type registration struct {
 	// address to run internal server on
 	address string
 
+	// bindHost is the host to listen on at the port of address, empty to listen on address
+	bindHost string
+
 	// filesystem path back to core
 	corePath string
 
 	// a unique identifier from core to identify the client with core on requests
 	fingerprint string
+
+	// the compression algorithm agreed on with core and the size from which it is used
+	compression          string
+	compressionThreshold int
+
+	// draining is true if an admin drained the service before it registered
+	draining bool
 }
 
 type State int
@@ -58,9 +71,34 @@ type Client struct {
 	// rpc connection handler to gmbhCore over Cabal
 	con *rpc.Connection
 
+	// cores are the addresses of every core that the client may use, cores[coreIndex] is the
+	// one in use. There is more than one only for a highly available cluster.
+	cores     []string
+	coreIndex int
+
 	// The map that handles function from the user's service
 	registeredFunctions map[string]HandlerFunc
 
+	// The map of stream handlers from the user's service
+	registeredStreams map[string]StreamHandlerFunc
+
+	// pools map [route]pool bounds the handlers running for each route
+	pools map[string]*handlerPool
+
+	// healthCheck is polled by core through Alive, nil if the user has not set one
+	healthCheck HealthCheck
+
+	// notReady is set while the last health check reported the service as not live or ready, peers
+	// that call it directly are turned away until it is
+	notReady bool
+
+	// draining is set while core has been told by an admin to stop routing to the service,
+	// peers that call it directly are turned away as well
+	draining bool
+
+	// compression counts the bytes saved by compressing data requests and responses
+	compression *rpc.CompressionStats
+
 	PongTime time.Duration
 
 	// the address of the cabal server that the client hosts itself on.
@@ -76,9 +114,16 @@ type Client struct {
 	//
 	// if the name is not found in the map, a whois request will be sent to gmbhCore
 	// where it will be determined if the service can make the connection. The resulting
-	// address will be stored in this map
+	// address will be stored in this map, or an empty one if core will only route the
+	// requests itself
 	whoIs map[string]string
 
+	// whoIsTokens map [name]token
+	//
+	// the token received from core along with a whoIs address. It is attached to every
+	// request sent directly to that service so that the service can verify the sender
+	whoIsTokens map[string]string
+
 	msgCounter int
 	mu         *sync.Mutex
 
@@ -110,7 +155,11 @@ func NewClient(opt ...Option) (*Client, error) {
 
 	g = &Client{
 		registeredFunctions: make(map[string]HandlerFunc),
+		registeredStreams:   make(map[string]StreamHandlerFunc),
+		pools:               make(map[string]*handlerPool),
+		compression:         rpc.NewCompressionStats(),
 		whoIs:               make(map[string]string),
+		whoIsTokens:         make(map[string]string),
 		mu:                  &sync.Mutex{},
 		PongTime:            time.Second * 45,
 		env:                 os.Getenv("ENV"),
@@ -136,12 +185,24 @@ func NewClient(opt ...Option) (*Client, error) {
 	// If the address back to core has been set using an environment variable, use that. Otherwise
 	// use the one from opts which defaults to the default set from the config package
 	if g.env == "C" {
-		g.opts.standalone.CoreAddress = os.Getenv("CORE")
+		// CORE may list every core of a cluster separated by commas
+		cores := strings.Split(os.Getenv("CORE"), ",")
+		g.opts.standalone.CoreAddress = cores[0]
+		g.opts.standalone.CoreAddresses = cores[1:]
 		print("using core address from env=%s", os.Getenv("CORE"))
 		g.myAddress = os.Getenv("ADDR")
+		if os.Getenv("BIND_HOST") != "" {
+			g.opts.standalone.BindHost = os.Getenv("BIND_HOST")
+		}
 	} else {
 		print("core address=%s", g.opts.standalone.CoreAddress)
 	}
+	g.cores = []string{g.opts.standalone.CoreAddress}
+	for _, addr := range g.opts.standalone.CoreAddresses {
+		if addr != "" && !g.isCore(addr) {
+			g.cores = append(g.cores, addr)
+		}
+	}
 
 	// @important -- the only service allowed to be named CoreData is the actual gmbhCore
 	if g.opts.service.Name == "CoreData" {
@@ -189,10 +250,11 @@ func (g *Client) Shutdown(src string) {
 	// print("Shutdown procedures started in client from " + src)
 	g.mu.Lock()
 	g.closed = true
+	fingerprint := g.getReg().fingerprint
 	g.reg = nil
 	g.mu.Unlock()
 
-	g.makeUnregisterRequest()
+	g.makeUnregisterRequest(fingerprint)
 	g.disconnect()
 
 	// print("shutdown, time=" + time.Now().Format(time.RFC3339))
@@ -200,13 +262,13 @@ func (g *Client) Shutdown(src string) {
 	defer os.Exit(0)
 }
 
-func (g *Client) resolveAddress(target string) string {
-
-	addr, ok := g.whoIs[target]
+// resolveAddress returns the address to send requests for target to along with the token
+// to send peers. The token is empty when the address is that of core.
+func (g *Client) resolveAddress(target string) (string, string) {
 
 	// address already stored in whoIs map
-	if ok {
-		return addr
+	if addr, token, ok := g.lookupAddress(target); ok {
+		return addr, token
 	}
 
 	// ask the core for the address
@@ -214,17 +276,73 @@ func (g *Client) resolveAddress(target string) string {
 
 	err := makeWhoIsRequest(target)
 	if err == nil {
-		return g.whoIs[target]
+		if addr, token, ok := g.lookupAddress(target); ok {
+			return addr, token
+		}
 	}
 
 	// send through to see if the core can process the request for us
-	return g.opts.standalone.CoreAddress
+	return g.coreAddress(), ""
+}
+
+// lookupAddress returns the address and token of target from the whoIs map, an empty
+// address there is sent through core
+func (g *Client) lookupAddress(target string) (string, string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	addr, ok := g.whoIs[target]
+	if ok && addr == "" {
+		return g.cores[g.coreIndex], "", true
+	}
+	return addr, g.whoIsTokens[target], ok
+}
+
+// forgetAddress removes the target from the whoIs map so that the next request to it will
+// be resolved through core again
+func (g *Client) forgetAddress(target string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.whoIs, target)
+	delete(g.whoIsTokens, target)
 }
 
 /**********************************************************************************
 **** Handling connection to gmbhCore
 **********************************************************************************/
 
+// coreAddress returns the address of the core in use
+func (g *Client) coreAddress() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.cores[g.coreIndex]
+}
+
+// isCore returns true if addr is one of the cores rather than a peer
+func (g *Client) isCore(addr string) bool {
+	for _, c := range g.cores {
+		if c == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// failover moves on from the core at addr, which could not be reached, to the next one of the
+// cluster. It returns false if there is no other core to move to.
+func (g *Client) failover(addr string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.cores) < 2 {
+		return false
+	}
+	// another request may have moved on already
+	if g.cores[g.coreIndex] == addr {
+		g.coreIndex = (g.coreIndex + 1) % len(g.cores)
+		print("could not reach core at %s; moving to %s", addr, g.cores[g.coreIndex])
+	}
+	return true
+}
+
 // disconnect from gmbh-core and go back into connecting mode
 func (g *Client) disconnect() {
 
@@ -271,17 +389,30 @@ func (g *Client) failed() {
 	}
 }
 
-func (g *Client) makeUnregisterRequest() {
-	client, ctx, can, err := rpc.GetCabalRequest(g.opts.standalone.CoreAddress, time.Second*5)
-	if err != nil {
-		panic(err)
-	}
-	defer can()
+// makeUnregisterRequest tells core that the service has shut down, proving that it is the
+// service with the fingerprint it registered with
+func (g *Client) makeUnregisterRequest(fingerprint string) {
 	request := &intrigue.ServiceUpdate{
 		Request: "shutdown.notif",
 		Message: g.opts.service.Name,
 	}
-	_, _ = client.UpdateRegistration(ctx, request)
+	for range g.cores {
+		addr := g.coreAddress()
+		client, ctx, can, err := rpc.GetCabalRequest(addr, time.Second*5)
+		if err != nil {
+			panic(err)
+		}
+		ctx = metadata.AppendToOutgoingContext(
+			ctx,
+			"sender", g.opts.service.Name,
+			"fingerprint", fingerprint,
+		)
+		_, err = client.UpdateRegistration(ctx, request)
+		can()
+		if grpc.Code(err) != codes.Unavailable || !g.failover(addr) {
+			return
+		}
+	}
 }
 
 // getReg gets the registration or an empty one, keeps from causing a panic
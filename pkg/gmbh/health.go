@@ -0,0 +1,76 @@
+package gmbh
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gmbh-micro/rpc/intrigue"
+)
+
+// HealthStatus is the result of a health check. A service that is not Live is broken, one
+// that is not Ready is up but cannot handle requests, for example while its database is down.
+// Core does not forward requests to a service until it is live and ready again, and marks a
+// running service that is not live as failed.
+type HealthStatus struct {
+	Live    bool
+	Ready   bool
+	Message string
+
+	// Details are shown along with the status, such as the state of each dependency
+	Details map[string]string
+}
+
+// HealthCheck reports the health of the service. ctx is done once core stops waiting for the
+// result.
+type HealthCheck func(ctx context.Context) HealthStatus
+
+// SetHealthCheck sets the function that core polls for the health of the service. Without one
+// the service is live and ready whenever it can be reached.
+func (g *Client) SetHealthCheck(check HealthCheck) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.healthCheck = check
+}
+
+// checkHealth runs the health check, one that panics is reported as not live
+func (g *Client) checkHealth(ctx context.Context) (status HealthStatus) {
+	g.mu.Lock()
+	check := g.healthCheck
+	g.mu.Unlock()
+	if check == nil {
+		return HealthStatus{Live: true, Ready: true}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			status = HealthStatus{Message: fmt.Sprintf("health check panicked; %v", r)}
+		}
+		g.mu.Lock()
+		g.notReady = !status.Live || !status.Ready
+		g.mu.Unlock()
+	}()
+	return check(ctx)
+}
+
+// isReady returns false if the last health check reported the service as not live or ready
+func (g *Client) isReady() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return !g.notReady
+}
+
+// isDraining returns true while core has been told to stop routing to the service
+func (g *Client) isDraining() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.draining
+}
+
+func (h HealthStatus) proto() *intrigue.Health {
+	return &intrigue.Health{
+		Live:    h.Live,
+		Ready:   h.Ready,
+		Message: h.Message,
+		Details: h.Details,
+	}
+}
@@ -1,5 +1,7 @@
 package gmbh
 
+import "time"
+
 const coreAddress = "localhost:49500"
 
 // Option functions set options from the client
@@ -26,6 +28,28 @@ type RuntimeOptions struct {
 	// Should the client run in verbose mode. in Verbose mode, debug information regarding
 	// the gmbh client will be printed to stdOut
 	Verbose bool
+
+	// HandlerLimits bound the number of handlers that may run at once for each route
+	// that does not have an entry in RouteLimits. The zero value is unbounded.
+	HandlerLimits HandlerLimits
+
+	// RouteLimits map[route]limits overrides HandlerLimits for individual routes
+	RouteLimits map[string]HandlerLimits
+}
+
+// HandlerLimits - user configurable, controls how many requests to a route are handled
+// at once and what happens to the requests that arrive while all handlers are busy
+type HandlerLimits struct {
+	// MaxConcurrent is the number of handlers that may run at once; zero is unlimited
+	MaxConcurrent int
+
+	// QueueDepth is the number of requests that may wait for a free handler. Requests
+	// arriving while the queue is full are rejected with service.overloaded
+	QueueDepth int
+
+	// QueueTimeout is how long a request may wait in the queue before it is rejected
+	// with service.overloaded. Zero waits until the request itself times out.
+	QueueTimeout time.Duration
 }
 
 // StandaloneOptions - user configurable, for use only without the service launcher or remotes
@@ -33,6 +57,15 @@ type StandaloneOptions struct {
 	// The address back to core
 	// NOTE: This will be overriden depending on environment
 	CoreAddress string
+
+	// CoreAddresses are the other cores of a highly available cluster. When the core in use
+	// cannot be reached the client moves on to the next one, starting with CoreAddress.
+	CoreAddresses []string
+
+	// BindHost is the host that the service listens on at the port that core assigned, such
+	// as 0.0.0.0 or the ip of one interface. It overrides the bind host from the config of
+	// core, and in container mode it can be set with BIND_HOST.
+	BindHost string
 }
 
 // ServiceOptions - user configurable, a name must be set, this is how other services will contact this one.
@@ -50,6 +83,28 @@ type ServiceOptions struct {
 	// NOTE: Any services where the group_id is undefined will be able to talk to
 	//       eachother freely.
 	PeerGroups []string
+
+	// RateLimit is the number of requests per second that core will forward to the
+	// service, with bursts of up to Burst requests. Zero is unlimited.
+	//
+	// NOTE: Limits set in the project config take precedence
+	RateLimit float64
+	Burst     int
+
+	// MaxInFlight is the number of requests that core will forward to the service
+	// before it has responded to them. Zero is unlimited.
+	MaxInFlight int
+
+	// Compression is the algorithm, "gzip" or "zstd", to use for data requests sent through
+	// core that are at least CompressionThreshold bytes. Core has the final say and may turn
+	// it off. When the threshold is zero the one from core is used.
+	Compression          string
+	CompressionThreshold int
+
+	// Port is the port that the service would like core to assign it. Core picks another
+	// one if it is taken, and zero lets core choose. It is not used in container mode where
+	// the service gives its own address.
+	Port int
 }
 
 var defaultOptions = options{
@@ -72,6 +127,8 @@ func SetRuntime(r RuntimeOptions) Option {
 	return func(o *options) {
 		o.runtime.Blocking = r.Blocking
 		o.runtime.Verbose = r.Verbose
+		o.runtime.HandlerLimits = r.HandlerLimits
+		o.runtime.RouteLimits = r.RouteLimits
 	}
 }
 
@@ -79,6 +136,8 @@ func SetRuntime(r RuntimeOptions) Option {
 func SetStandalone(s StandaloneOptions) Option {
 	return func(o *options) {
 		o.standalone.CoreAddress = s.CoreAddress
+		o.standalone.CoreAddresses = s.CoreAddresses
+		o.standalone.BindHost = s.BindHost
 	}
 }
 
@@ -90,5 +149,11 @@ func SetService(s ServiceOptions) Option {
 		if len(s.PeerGroups) != 0 {
 			o.service.PeerGroups = s.PeerGroups
 		}
+		o.service.RateLimit = s.RateLimit
+		o.service.Burst = s.Burst
+		o.service.MaxInFlight = s.MaxInFlight
+		o.service.Compression = s.Compression
+		o.service.CompressionThreshold = s.CompressionThreshold
+		o.service.Port = s.Port
 	}
 }
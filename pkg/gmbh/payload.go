@@ -1,17 +1,55 @@
 package gmbh
 
 import (
-	b64 "encoding/base64"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/gmbh-micro/rpc/intrigue"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
 )
 
 // Payload handles data that is to be transported between services
+//
+// Values added with Append are sent as JSON, which every gmbh client understands. Values
+// added with the typed Append functions (AppendString, AppendBytes, ...) are sent in the
+// protobuf fields of the same type, skipping the JSON and base64 round trips. The typed
+// fields are only read by the matching typed getters, so a service receiving from a client
+// that does not use them, such as the Node and Python clients, should keep using Append.
+//
+// A payload created with NewPayloadWithCodec marshals the values added with Append and Encode
+// with that codec instead of JSON. Protobuf messages can be added to any payload with
+// AppendProto and are sent as they are.
 type Payload struct {
 	// JSON label->json; the object will be marshalled into JSON
 	JSON map[string][]byte
+
+	// codec is used for the values added with Append when it is not nil, the values are then
+	// kept in encoded instead of JSON
+	codec   Codec
+	encoded map[string][]byte
+
+	// messages label->protobuf message
+	messages map[string]*any.Any
+
+	// wrapped is true when the payload was received from another service, in which case
+	// each value in JSON is itself a base64 encoded JSON string. See Proto.
+	wrapped bool
+
+	// typed label->value; each map is sent in the protobuf field of its type
+	text    map[string]string
+	bools   map[string]bool
+	bytes   map[string][]byte
+	ints    map[string]int32
+	int64s  map[string]int64
+	uints   map[string]uint32
+	uint64s map[string]uint64
+	doubles map[string]float64
+	floats  map[string]float32
 }
 
 // NewPayload returns an empty new payload
@@ -19,22 +57,57 @@ func NewPayload() *Payload {
 	return &Payload{}
 }
 
+// NewPayloadWithCodec returns an empty new payload that marshals its values with the codec
+// registered as name. The receiving service must have a codec with the same name.
+func NewPayloadWithCodec(name string) (*Payload, error) {
+	c, err := getCodec(name)
+	if err != nil {
+		return nil, err
+	}
+	if c.Name() == JSONCodec {
+		return &Payload{}, nil
+	}
+	return &Payload{codec: c}, nil
+}
+
+// Codec returns the name of the codec used by the payload
+func (p *Payload) Codec() string {
+	if p == nil || p.codec == nil {
+		return JSONCodec
+	}
+	return p.codec.Name()
+}
+
+// codecName returns the name of the codec to send with the payload, which is empty for
+// JSON so that clients without codec support do not see a difference
+func (p *Payload) codecName() string {
+	if p == nil || p.codec == nil {
+		return ""
+	}
+	return p.codec.Name()
+}
+
 // Get returns the value of payload.JSON at key
 func (p *Payload) Get(key string) interface{} {
 	if p == nil {
 		return make(map[string]interface{})
 	}
 
+	var obj interface{}
+	if p.codec != nil {
+		p.decodeValue(key, &obj)
+		return obj
+	}
+
 	if p.JSON == nil {
 		return make(map[string]interface{})
 	}
 
-	var obj interface{}
-	v, ok := p.JSON[key]
-	if !ok {
+	v, err := p.raw(key)
+	if err != nil {
 		return obj
 	}
-	err := json.Unmarshal(v, &obj)
+	err = json.Unmarshal(v, &obj)
 	if err != nil {
 		return obj
 	}
@@ -53,6 +126,17 @@ func (p *Payload) GetAsInt(key string) int {
 	case string:
 		return 0
 	}
+
+	// other codecs may decode to any size of number
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint())
+	case reflect.Float32:
+		return int(v.Float())
+	}
 	return 0
 }
 
@@ -69,24 +153,31 @@ func (p *Payload) GetAsString(key string) string {
 		return value.(string)
 	}
 
-	str, ok := value.(string)
-	if !ok {
-		return ""
-	}
-	bstr, err := b64.URLEncoding.DecodeString(str)
-	if err != nil {
-		return str
-	}
-
-	str = string(bstr)
-	if str[0] == '"' && str[len(str)-1] == '"' {
-		str = str[1 : len(str)-1]
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf("%d", v.Int())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("%d", v.Uint())
+	case reflect.Float32:
+		return fmt.Sprintf("%f", v.Float())
 	}
-	return str
+	return ""
 }
 
 // Append adds a value to Payload.JSON; overwrites current value as default behavior.
 func (p *Payload) Append(key string, value interface{}) {
+	if p.codec != nil {
+		bytes, err := p.codec.Marshal(value)
+		if err != nil {
+			return
+		}
+		if p.encoded == nil {
+			p.encoded = make(map[string][]byte)
+		}
+		p.encoded[key] = bytes
+		return
+	}
 	if p.JSON == nil {
 		p.JSON = make(map[string][]byte)
 	}
@@ -94,7 +185,276 @@ func (p *Payload) Append(key string, value interface{}) {
 	if err != nil {
 		return
 	}
-	p.JSON[key] = bytes
+	p.set(key, bytes)
+}
+
+// Encode adds each field of v to the payload as if it was added with Append, using the
+// json struct tags of v for the keys. v must encode to a JSON object, ie a struct or a map.
+func (p *Payload) Encode(v interface{}) error {
+	if p.codec != nil {
+		return p.encodeWithCodec(v)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("payload.Encode: %s", err.Error())
+	}
+	fields := make(map[string]json.RawMessage)
+	err = json.Unmarshal(b, &fields)
+	if err != nil {
+		return fmt.Errorf("payload.Encode: value must encode to a JSON object")
+	}
+	if p.JSON == nil {
+		p.JSON = make(map[string][]byte)
+	}
+	for k, f := range fields {
+		p.set(k, []byte(f))
+	}
+	return nil
+}
+
+// set stores the JSON value at key, wrapping it like the rest of the values if the payload
+// was received from another service
+func (p *Payload) set(key string, value []byte) {
+	if p.wrapped {
+		value, _ = json.Marshal(value)
+	}
+	p.JSON[key] = value
+}
+
+// Decode unmarshals the value at key into v. The key may be a path through nested objects
+// and arrays separated by dots, ie "user.address.city" or "users.0.name". An error is
+// returned if any part of the path cannot be found or the value does not fit v.
+func (p *Payload) Decode(key string, v interface{}) error {
+	path := strings.Split(key, ".")
+	if p != nil && p.codec != nil {
+		return p.decodeWithCodec(path, v)
+	}
+	raw, err := p.raw(path[0])
+	if err != nil {
+		return err
+	}
+	for i, part := range path[1:] {
+		raw, err = lookupJSON(raw, part)
+		if err != nil {
+			return fmt.Errorf("payload.Decode: %s at %s", err.Error(), strings.Join(path[:i+2], "."))
+		}
+	}
+	err = json.Unmarshal(raw, v)
+	if err != nil {
+		return fmt.Errorf("payload.Decode: %s at %s", err.Error(), key)
+	}
+	return nil
+}
+
+// DecodeInto unmarshals the whole payload into v as if every key was a field of one JSON
+// object. It is the reverse of Encode.
+func (p *Payload) DecodeInto(v interface{}) error {
+	if p != nil && p.codec != nil {
+		return p.decodeIntoWithCodec(v)
+	}
+	fields := make(map[string]json.RawMessage)
+	if p != nil {
+		for k := range p.JSON {
+			raw, err := p.raw(k)
+			if err != nil {
+				return err
+			}
+			fields[k] = raw
+		}
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("payload.DecodeInto: %s", err.Error())
+	}
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return fmt.Errorf("payload.DecodeInto: %s", err.Error())
+	}
+	return nil
+}
+
+// raw returns the JSON value at key, unwrapping it if it was received from another service
+func (p *Payload) raw(key string) (json.RawMessage, error) {
+	if p == nil || p.JSON == nil {
+		return nil, fmt.Errorf("payload: key %s not found", key)
+	}
+	v, ok := p.JSON[key]
+	if !ok {
+		return nil, fmt.Errorf("payload: key %s not found", key)
+	}
+	if !p.wrapped {
+		return v, nil
+	}
+	var encoded []byte
+	err := json.Unmarshal(v, &encoded)
+	if err != nil {
+		return nil, fmt.Errorf("payload: could not unwrap %s; %s", key, err.Error())
+	}
+	return encoded, nil
+}
+
+// lookupJSON returns the value of the field name in the JSON object raw, or the element at
+// index name if raw is an array
+func lookupJSON(raw json.RawMessage, name string) (json.RawMessage, error) {
+	obj := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		v, ok := obj[name]
+		if !ok {
+			return nil, fmt.Errorf("field not found")
+		}
+		return v, nil
+	}
+	arr := []json.RawMessage{}
+	if err := json.Unmarshal(raw, &arr); err == nil {
+		i, err := strconv.Atoi(name)
+		if err != nil || i < 0 || i >= len(arr) {
+			return nil, fmt.Errorf("index not found")
+		}
+		return arr[i], nil
+	}
+	return nil, fmt.Errorf("value is not an object or array")
+}
+
+// encodeWithCodec is Encode for payloads that do not use JSON
+func (p *Payload) encodeWithCodec(v interface{}) error {
+	b, err := p.codec.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("payload.Encode: %s", err.Error())
+	}
+	fields := make(map[string]interface{})
+	err = p.codec.Unmarshal(b, &fields)
+	if err != nil {
+		return fmt.Errorf("payload.Encode: value must encode to a map")
+	}
+	if p.encoded == nil {
+		p.encoded = make(map[string][]byte)
+	}
+	for k, f := range fields {
+		b, err := p.codec.Marshal(f)
+		if err != nil {
+			return fmt.Errorf("payload.Encode: %s at %s", err.Error(), k)
+		}
+		p.encoded[k] = b
+	}
+	return nil
+}
+
+// decodeWithCodec is Decode for payloads that do not use JSON. The codec cannot look inside
+// its own encoding, so a nested value is decoded generically, found, and then marshalled
+// again to be decoded into v.
+func (p *Payload) decodeWithCodec(path []string, v interface{}) error {
+	if len(path) == 1 {
+		return p.decodeValue(path[0], v)
+	}
+	var obj interface{}
+	err := p.decodeValue(path[0], &obj)
+	if err != nil {
+		return err
+	}
+	for i, part := range path[1:] {
+		obj, err = lookupValue(obj, part)
+		if err != nil {
+			return fmt.Errorf("payload.Decode: %s at %s", err.Error(), strings.Join(path[:i+2], "."))
+		}
+	}
+	b, err := p.codec.Marshal(obj)
+	if err == nil {
+		err = p.codec.Unmarshal(b, v)
+	}
+	if err != nil {
+		return fmt.Errorf("payload.Decode: %s at %s", err.Error(), strings.Join(path, "."))
+	}
+	return nil
+}
+
+// decodeIntoWithCodec is DecodeInto for payloads that do not use JSON
+func (p *Payload) decodeIntoWithCodec(v interface{}) error {
+	fields := make(map[string]interface{})
+	for k := range p.encoded {
+		var obj interface{}
+		err := p.decodeValue(k, &obj)
+		if err != nil {
+			return err
+		}
+		fields[k] = obj
+	}
+	b, err := p.codec.Marshal(fields)
+	if err == nil {
+		err = p.codec.Unmarshal(b, v)
+	}
+	if err != nil {
+		return fmt.Errorf("payload.DecodeInto: %s", err.Error())
+	}
+	return nil
+}
+
+// decodeValue unmarshals the value at key with the codec of the payload
+func (p *Payload) decodeValue(key string, v interface{}) error {
+	b, ok := p.encoded[key]
+	if !ok {
+		return fmt.Errorf("payload: key %s not found", key)
+	}
+	err := p.codec.Unmarshal(b, v)
+	if err != nil {
+		return fmt.Errorf("payload.Decode: %s at %s", err.Error(), key)
+	}
+	return nil
+}
+
+// lookupValue is lookupJSON for values that have already been decoded
+func lookupValue(obj interface{}, name string) (interface{}, error) {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		v, ok := o[name]
+		if !ok {
+			return nil, fmt.Errorf("field not found")
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		v, ok := o[name]
+		if !ok {
+			return nil, fmt.Errorf("field not found")
+		}
+		return v, nil
+	case []interface{}:
+		i, err := strconv.Atoi(name)
+		if err != nil || i < 0 || i >= len(o) {
+			return nil, fmt.Errorf("index not found")
+		}
+		return o[i], nil
+	}
+	return nil, fmt.Errorf("value is not an object or array")
+}
+
+// AppendProto adds a protobuf message to the payload. The message is sent as an Any along
+// with its type so that the receiver can read it with GetProto.
+func (p *Payload) AppendProto(key string, m proto.Message) error {
+	a, err := ptypes.MarshalAny(m)
+	if err != nil {
+		return fmt.Errorf("payload.AppendProto: %s", err.Error())
+	}
+	if p.messages == nil {
+		p.messages = make(map[string]*any.Any)
+	}
+	p.messages[key] = a
+	return nil
+}
+
+// GetProto unmarshals the message added with AppendProto at key into m. An error is returned
+// if there is no message at key or it is not of the same type as m.
+func (p *Payload) GetProto(key string, m proto.Message) error {
+	if p == nil || p.messages == nil {
+		return fmt.Errorf("payload: key %s not found", key)
+	}
+	a, ok := p.messages[key]
+	if !ok {
+		return fmt.Errorf("payload: key %s not found", key)
+	}
+	err := ptypes.UnmarshalAny(a, m)
+	if err != nil {
+		return fmt.Errorf("payload.GetProto: %s", err.Error())
+	}
+	return nil
 }
 
 // AppendDataMap adds all values of the input map to the payload.JSON
@@ -110,13 +470,195 @@ func (p *Payload) AppendDataMap(inputMap map[string][]byte) {
 	}
 }
 
+// AppendString adds a string to the payload without encoding it as JSON
+func (p *Payload) AppendString(key, value string) {
+	if p.text == nil {
+		p.text = make(map[string]string)
+	}
+	p.text[key] = value
+}
+
+// AppendBytes adds raw bytes to the payload without encoding them as JSON or base64
+func (p *Payload) AppendBytes(key string, value []byte) {
+	if p.bytes == nil {
+		p.bytes = make(map[string][]byte)
+	}
+	p.bytes[key] = value
+}
+
+// AppendBool adds a bool to the payload without encoding it as JSON
+func (p *Payload) AppendBool(key string, value bool) {
+	if p.bools == nil {
+		p.bools = make(map[string]bool)
+	}
+	p.bools[key] = value
+}
+
+// AppendInt adds an int to the payload without encoding it as JSON
+func (p *Payload) AppendInt(key string, value int) {
+	p.AppendInt64(key, int64(value))
+}
+
+// AppendInt32 adds an int32 to the payload without encoding it as JSON
+func (p *Payload) AppendInt32(key string, value int32) {
+	if p.ints == nil {
+		p.ints = make(map[string]int32)
+	}
+	p.ints[key] = value
+}
+
+// AppendInt64 adds an int64 to the payload without encoding it as JSON
+func (p *Payload) AppendInt64(key string, value int64) {
+	if p.int64s == nil {
+		p.int64s = make(map[string]int64)
+	}
+	p.int64s[key] = value
+}
+
+// AppendUint32 adds a uint32 to the payload without encoding it as JSON
+func (p *Payload) AppendUint32(key string, value uint32) {
+	if p.uints == nil {
+		p.uints = make(map[string]uint32)
+	}
+	p.uints[key] = value
+}
+
+// AppendUint64 adds a uint64 to the payload without encoding it as JSON
+func (p *Payload) AppendUint64(key string, value uint64) {
+	if p.uint64s == nil {
+		p.uint64s = make(map[string]uint64)
+	}
+	p.uint64s[key] = value
+}
+
+// AppendFloat32 adds a float32 to the payload without encoding it as JSON
+func (p *Payload) AppendFloat32(key string, value float32) {
+	if p.floats == nil {
+		p.floats = make(map[string]float32)
+	}
+	p.floats[key] = value
+}
+
+// AppendFloat64 adds a float64 to the payload without encoding it as JSON
+func (p *Payload) AppendFloat64(key string, value float64) {
+	if p.doubles == nil {
+		p.doubles = make(map[string]float64)
+	}
+	p.doubles[key] = value
+}
+
+// GetString returns the string added with AppendString at key, else the empty string
+func (p *Payload) GetString(key string) string {
+	if p == nil {
+		return ""
+	}
+	return p.text[key]
+}
+
+// GetBytes returns the bytes added with AppendBytes at key, else nil
+func (p *Payload) GetBytes(key string) []byte {
+	if p == nil {
+		return nil
+	}
+	return p.bytes[key]
+}
+
+// GetBool returns the bool added with AppendBool at key, else false
+func (p *Payload) GetBool(key string) bool {
+	if p == nil {
+		return false
+	}
+	return p.bools[key]
+}
+
+// GetInt returns the integer added with one of the int Append functions at key, else 0
+func (p *Payload) GetInt(key string) int {
+	return int(p.GetInt64(key))
+}
+
+// GetInt32 returns the int32 added with AppendInt32 at key, else 0
+func (p *Payload) GetInt32(key string) int32 {
+	if p == nil {
+		return 0
+	}
+	return p.ints[key]
+}
+
+// GetInt64 returns the integer added with one of the int Append functions at key, else 0
+func (p *Payload) GetInt64(key string) int64 {
+	if p == nil {
+		return 0
+	}
+	if v, ok := p.int64s[key]; ok {
+		return v
+	}
+	return int64(p.ints[key])
+}
+
+// GetUint32 returns the uint32 added with AppendUint32 at key, else 0
+func (p *Payload) GetUint32(key string) uint32 {
+	if p == nil {
+		return 0
+	}
+	return p.uints[key]
+}
+
+// GetUint64 returns the unsigned integer added with one of the uint Append functions at
+// key, else 0
+func (p *Payload) GetUint64(key string) uint64 {
+	if p == nil {
+		return 0
+	}
+	if v, ok := p.uint64s[key]; ok {
+		return v
+	}
+	return uint64(p.uints[key])
+}
+
+// GetFloat32 returns the float32 added with AppendFloat32 at key, else 0
+func (p *Payload) GetFloat32(key string) float32 {
+	if p == nil {
+		return 0
+	}
+	return p.floats[key]
+}
+
+// GetFloat64 returns the float added with one of the float Append functions at key, else 0
+func (p *Payload) GetFloat64(key string) float64 {
+	if p == nil {
+		return 0
+	}
+	if v, ok := p.doubles[key]; ok {
+		return v
+	}
+	return float64(p.floats[key])
+}
+
 // Proto ; parses payload to protocall buffer
+//
+// The values in Payload.JSON are marshalled a second time, which is what the Node and
+// Python clients expect to receive. Use the typed Append functions to avoid it.
 func (p *Payload) Proto() *intrigue.Payload {
 	proto := &intrigue.Payload{}
 	if p == nil {
 		return proto
 	}
-	if p.JSON != nil {
+	proto.TextFields = p.text
+	proto.BoolFields = p.bools
+	proto.ByteFields = p.bytes
+	proto.IntFields = p.ints
+	proto.Int64Fields = p.int64s
+	proto.UintFields = p.uints
+	proto.Uint64Fields = p.uint64s
+	proto.DoubleFields = p.doubles
+	proto.FloatFields = p.floats
+	proto.Encoded = p.encoded
+	proto.Data = p.messages
+
+	// a payload received from another service is already wrapped and is passed on as is
+	if p.wrapped {
+		proto.JSON = p.JSON
+	} else if p.JSON != nil {
 		m := make(map[string][]byte)
 		for k, v := range p.JSON {
 			j, e := json.Marshal(v)
@@ -131,9 +673,97 @@ func (p *Payload) Proto() *intrigue.Payload {
 	return proto
 }
 
-// payloadFromProto ;
-func payloadFromProto(proto *intrigue.Payload) *Payload {
+// payloadFromProto ; codec is the name sent with the request or response
+func payloadFromProto(proto *intrigue.Payload, codec string) (*Payload, error) {
 	p := &Payload{}
+	c, err := getCodec(codec)
+	if err != nil {
+		return p, err
+	}
+	if c.Name() != JSONCodec {
+		p.codec = c
+	}
+	p.encoded = proto.GetEncoded()
+	p.messages = proto.GetData()
 	p.JSON = proto.GetJSON()
-	return p
+	p.wrapped = true
+	p.text = proto.GetTextFields()
+	p.bools = proto.GetBoolFields()
+	p.bytes = proto.GetByteFields()
+	p.ints = proto.GetIntFields()
+	p.int64s = proto.GetInt64Fields()
+	p.uints = proto.GetUintFields()
+	p.uint64s = proto.GetUint64Fields()
+	p.doubles = proto.GetDoubleFields()
+	p.floats = proto.GetFloatFields()
+	return p, nil
+}
+
+// PayloadFromJSON returns the protocol buffer of a payload with each field of the JSON object
+// data, the same as one filled with Encode. It is for relaying JSON from outside of gmbh, as
+// the cli and the websocket of core do.
+func PayloadFromJSON(data []byte) (*intrigue.Payload, error) {
+	p := NewPayload()
+	if len(strings.TrimSpace(string(data))) != 0 {
+		if err := p.Encode(json.RawMessage(data)); err != nil {
+			return nil, err
+		}
+	}
+	return p.Proto(), nil
+}
+
+// JSONFromPayload returns every value of a payload received with codec as one JSON object,
+// whether it was sent as JSON, encoded with the codec or in one of the typed fields. Protobuf
+// messages are left as their type url and bytes.
+func JSONFromPayload(proto *intrigue.Payload, codec string) (json.RawMessage, error) {
+	p, err := payloadFromProto(proto, codec)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	for k := range p.JSON {
+		raw, err := p.raw(k)
+		if err != nil {
+			return nil, err
+		}
+		fields[k] = raw
+	}
+	for k := range p.encoded {
+		var v interface{}
+		if err := p.decodeValue(k, &v); err != nil {
+			return nil, err
+		}
+		fields[k] = v
+	}
+	for k, v := range p.messages {
+		fields[k] = v
+	}
+	for k, v := range p.text {
+		fields[k] = v
+	}
+	for k, v := range p.bools {
+		fields[k] = v
+	}
+	for k, v := range p.bytes {
+		fields[k] = v
+	}
+	for k, v := range p.ints {
+		fields[k] = v
+	}
+	for k, v := range p.int64s {
+		fields[k] = v
+	}
+	for k, v := range p.uints {
+		fields[k] = v
+	}
+	for k, v := range p.uint64s {
+		fields[k] = v
+	}
+	for k, v := range p.doubles {
+		fields[k] = v
+	}
+	for k, v := range p.floats {
+		fields[k] = v
+	}
+	return json.Marshal(fields)
 }
@@ -0,0 +1,154 @@
+package gmbh
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// errOverloaded is returned to the sender when a route has no free handler and its queue
+// is full or the request waited in the queue for longer than the queue timeout
+var errOverloaded = errors.New("service.overloaded")
+
+// handlerPool bounds the number of handlers running at once for a single route. Requests
+// that arrive while every handler is busy wait in a queue until one is free.
+type handlerPool struct {
+	limits HandlerLimits
+
+	// slots holds a value for every running handler
+	slots chan struct{}
+
+	// waiting is the number of requests in the queue
+	waiting int
+
+	// counters reported in the summary
+	handled  int64
+	rejected int64
+	timeouts int64
+
+	mu *sync.Mutex
+}
+
+func newHandlerPool(limits HandlerLimits) *handlerPool {
+	p := &handlerPool{
+		limits: limits,
+		mu:     &sync.Mutex{},
+	}
+	if limits.MaxConcurrent > 0 {
+		p.slots = make(chan struct{}, limits.MaxConcurrent)
+	}
+	return p
+}
+
+// acquire blocks until a handler is free. An error is returned if the queue is full, the
+// queue timeout is reached or ctx is done first.
+func (p *handlerPool) acquire(ctx context.Context) error {
+	if p.slots == nil {
+		p.count(&p.handled)
+		return nil
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+		p.count(&p.handled)
+		return nil
+	default:
+	}
+
+	p.mu.Lock()
+	if p.waiting >= p.limits.QueueDepth {
+		p.rejected++
+		p.mu.Unlock()
+		return errOverloaded
+	}
+	p.waiting++
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		p.waiting--
+		p.mu.Unlock()
+	}()
+
+	var timeout <-chan time.Time
+	if p.limits.QueueTimeout > 0 {
+		timer := time.NewTimer(p.limits.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+		p.count(&p.handled)
+		return nil
+	case <-timeout:
+	case <-ctx.Done():
+	}
+	p.count(&p.timeouts)
+	return errOverloaded
+}
+
+// release frees the handler taken by acquire
+func (p *handlerPool) release() {
+	if p.slots != nil {
+		<-p.slots
+	}
+}
+
+// metrics returns the counters of the pool prefixed with the route name
+func (p *handlerPool) metrics(route string) map[string]int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	prefix := "route." + route + "."
+	return map[string]int64{
+		prefix + "handled":  p.handled,
+		prefix + "rejected": p.rejected,
+		prefix + "timeouts": p.timeouts,
+		prefix + "running":  int64(len(p.slots)),
+		prefix + "queued":   int64(p.waiting),
+		prefix + "max":      int64(p.limits.MaxConcurrent),
+	}
+}
+
+func (p *handlerPool) count(c *int64) {
+	p.mu.Lock()
+	*c++
+	p.mu.Unlock()
+}
+
+// getPool returns the pool for route, creating it with the limits from the runtime options
+// the first time that it is needed
+func (g *Client) getPool(route string) *handlerPool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p, ok := g.pools[route]
+	if !ok {
+		limits, ok := g.opts.runtime.RouteLimits[route]
+		if !ok {
+			limits = g.opts.runtime.HandlerLimits
+		}
+		p = newHandlerPool(limits)
+		g.pools[route] = p
+	}
+	return p
+}
+
+// poolMetrics returns the counters of all pools for the summary
+func (g *Client) poolMetrics() map[string]int64 {
+	g.mu.Lock()
+	pools := make(map[string]*handlerPool, len(g.pools))
+	for k, v := range g.pools {
+		pools[k] = v
+	}
+	g.mu.Unlock()
+
+	ret := make(map[string]int64)
+	for route, p := range pools {
+		for k, v := range p.metrics(route) {
+			ret[k] = v
+		}
+	}
+	ret["routes"] = int64(len(pools))
+	return ret
+}
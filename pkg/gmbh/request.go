@@ -1,6 +1,10 @@
 package gmbh
 
-import "github.com/gmbh-micro/rpc/intrigue"
+import (
+	"errors"
+
+	"github.com/gmbh-micro/rpc/intrigue"
+)
 
 // Request is the publically exposed requester between services in gmbh
 type Request struct {
@@ -28,6 +32,18 @@ func (r *Request) GetPayload() *Payload {
 	return r.payload
 }
 
+// GetCodec returns the name of the codec of the request payload. The sender would also like
+// the response in this codec, see Responder.NewPayload.
+func (r *Request) GetCodec() string {
+	return r.GetPayload().Codec()
+}
+
+// GetSender returns the name of the service that sent the request. The sender has been
+// verified by gmbh before the request reaches a handler.
+func (r *Request) GetSender() string {
+	return r.GetTransport().sender
+}
+
 // SetPayload for the request
 func (r *Request) SetPayload(p *Payload) {
 	r.payload = p
@@ -54,15 +70,22 @@ func (r *Request) proto() *intrigue.Request {
 	}
 	if r.payload != nil {
 		ir.Pload = r.payload.Proto()
+		ir.Codec = r.payload.codecName()
 	}
 	return ir
 }
 
-func requestFromProto(r *intrigue.Request) Request {
+// requestFromProto returns an error if the payload was sent with a codec that has not been
+// registered with this service
+func requestFromProto(r *intrigue.Request) (Request, error) {
+	p, err := payloadFromProto(r.GetPload(), r.GetCodec())
+	if err != nil {
+		return Request{}, errors.New(errCodecUnsupported)
+	}
 	return Request{
 		transport: transportFromProto(r.GetTport()),
-		payload:   payloadFromProto(r.GetPload()),
-	}
+		payload:   p,
+	}, nil
 }
 
 // Transport handles data regarding the endpoints of a Request and MUST be
@@ -13,6 +13,9 @@ type Responder struct {
 
 	// Errors as reported by the client during data calculation
 	err string
+
+	// codec is the codec that the sender of the request would like the response in
+	codec string
 }
 
 // proto returns the gproto Request object corresponding to the current
@@ -22,7 +25,18 @@ func (r *Responder) proto() *intrigue.Responder {
 		Pload: r.payload.Proto(),
 		Tport: r.transport.proto(),
 		Err:   r.err,
+		Codec: r.payload.codecName(),
+	}
+}
+
+// NewPayload returns an empty payload using the codec of the request that is being
+// responded to
+func (r *Responder) NewPayload() *Payload {
+	p, err := NewPayloadWithCodec(r.codec)
+	if err != nil {
+		return NewPayload()
 	}
+	return p
 }
 
 // SetPayload for the request
@@ -49,7 +63,11 @@ func responderFromProto(r intrigue.Responder) Responder {
 	}
 
 	if r.Pload != nil {
-		ret.SetPayload(payloadFromProto(r.GetPload()))
+		p, err := payloadFromProto(r.GetPload(), r.GetCodec())
+		if err != nil {
+			ret.err = errCodecUnsupported
+		}
+		ret.SetPayload(p)
 	}
 
 	if r.Tport != nil {
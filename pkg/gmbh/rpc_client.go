@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gmbh-micro/rpc"
@@ -18,9 +19,23 @@ import (
 ** RPCClient
 **********************************************************************************/
 
+// register with core, moving on to the next core of a cluster if it cannot be reached
 func register() (*registration, error) {
+	var reg *registration
+	var err error
+	for range g.cores {
+		addr := g.coreAddress()
+		reg, err = registerWith(addr)
+		if err == nil || err.Error() != "registration.gmbhUnavailable" || !g.failover(addr) {
+			break
+		}
+	}
+	return reg, err
+}
 
-	client, ctx, can, err := rpc.GetCabalRequest(g.opts.standalone.CoreAddress, time.Second*3)
+func registerWith(addr string) (*registration, error) {
+
+	client, ctx, can, err := rpc.GetCabalRequest(addr, time.Second*3)
 	if err != nil {
 		return nil, errors.New("registration.gmbhUnavailable")
 	}
@@ -28,11 +43,16 @@ func register() (*registration, error) {
 
 	request := intrigue.NewServiceRequest{
 		Service: &intrigue.NewService{
-			Name:       g.opts.service.Name,
-			Aliases:    g.opts.service.Aliases,
-			PeerGroups: g.opts.service.PeerGroups,
-			IsClient:   true,
-			IsServer:   true,
+			Name:        g.opts.service.Name,
+			Aliases:     g.opts.service.Aliases,
+			PeerGroups:  g.opts.service.PeerGroups,
+			IsClient:    true,
+			IsServer:    true,
+			RateLimit:   g.opts.service.RateLimit,
+			Burst:       int32(g.opts.service.Burst),
+			MaxInFlight: int32(g.opts.service.MaxInFlight),
+			Compression: g.opts.service.Compression,
+			Port:        int32(g.opts.service.Port),
 		},
 		Address: g.myAddress,
 		Env:     g.env,
@@ -52,21 +72,44 @@ func register() (*registration, error) {
 		reg := reply.GetServiceInfo()
 
 		r := &registration{
-			id:          reg.GetID(),
-			address:     reg.GetAddress(),
-			fingerprint: reg.GetFingerprint(),
+			id:                   reg.GetID(),
+			address:              reg.GetAddress(),
+			fingerprint:          reg.GetFingerprint(),
+			compression:          reg.GetCompression(),
+			compressionThreshold: int(reg.GetCompressionThreshold()),
+			bindHost:             reg.GetBindHost(),
+			draining:             reg.GetDraining(),
+		}
+		if g.opts.standalone.BindHost != "" {
+			r.bindHost = g.opts.standalone.BindHost
+		}
+		if g.opts.service.CompressionThreshold != 0 {
+			r.compressionThreshold = g.opts.service.CompressionThreshold
 		}
 		return r, nil
 	}
+
+	// the cluster is electing a leader or has lost its majority, try again later
+	if strings.HasPrefix(reply.GetError(), "cluster.") || reply.GetError() == "registry.unavailable" {
+		print("core could not register the service; err=%s", reply.GetError())
+		return nil, errors.New("registration.gmbhUnavailable")
+	}
 	return nil, errors.New(reply.GetMessage())
 }
 
 func makeDataRequest(target, method string, data *Payload) (Responder, error) {
+	return sendDataRequest(target, method, data, len(g.cores)-1)
+}
 
-	addr := g.resolveAddress(target)
+// sendDataRequest sends the request directly to target or through core. Requests through a
+// core that cannot be reached are sent again through the next core of the cluster, up to
+// failovers times.
+func sendDataRequest(target, method string, data *Payload, failovers int) (Responder, error) {
+
+	addr, token := g.resolveAddress(target)
 
 	t := time.Now()
-	client, ctx, can, err := rpc.GetCabalRequest(addr, time.Second)
+	client, ctx, can, err := rpc.GetCabalRequest(addr, time.Second, grpc.WithStatsHandler(g.compression))
 	if err != nil {
 		return Responder{}, errors.New("data.gmbhUnavailable")
 	}
@@ -80,17 +123,41 @@ func makeDataRequest(target, method string, data *Payload) (Responder, error) {
 				Sender: g.opts.service.Name,
 			},
 			Pload: data.Proto(),
+			Codec: data.codecName(),
 		},
 	}
 
+	// core knows the fingerprint, peers are sent the token that core issued instead.
+	// Compression was only agreed on with core so peers are always sent uncompressed.
+	var opts []grpc.CallOption
+	direct := !g.isCore(addr)
+	if direct {
+		ctx = metadata.AppendToOutgoingContext(
+			ctx,
+			"sender", g.opts.service.Name,
+			"token", token,
+		)
+	} else {
+		ctx = metadata.AppendToOutgoingContext(
+			ctx,
+			"sender", g.opts.service.Name,
+			"fingerprint", g.getReg().fingerprint,
+		)
+		reg := g.getReg()
+		opts = rpc.CompressionOption(reg.compression, reg.compressionThreshold, &request)
+	}
+
 	mcs := strconv.Itoa(g.msgCounter)
 	g.msgCounter++
 	if g.env != "C" || os.Getenv("LOGGING") == "1" {
 		print("<=" + mcs + "= target: " + target + ", method: " + method)
 	}
 
-	reply, err := client.Data(ctx, &request)
+	reply, err := client.Data(ctx, &request, opts...)
 	if err != nil {
+		if !direct && failovers > 0 && grpc.Code(err) == codes.Unavailable && g.failover(addr) {
+			return sendDataRequest(target, method, data, failovers-1)
+		}
 		r := Responder{err: err.Error()}
 		return r, err
 
@@ -99,6 +166,15 @@ func makeDataRequest(target, method string, data *Payload) (Responder, error) {
 		print(" =" + mcs + "=> " + "time=" + time.Since(t).String())
 	}
 
+	if reply.GetError() != "" {
+		// the token is stale, most likely because core has since restarted, or the target is
+		// not ready or draining; either way core is asked again on the next request
+		if direct && (reply.GetError() == "sender.unverified" || reply.GetError() == "service.notReady" || reply.GetError() == "service.draining") {
+			g.forgetAddress(target)
+		}
+		return Responder{err: reply.GetError()}, errors.New(reply.GetError())
+	}
+
 	if reply.Responder == nil {
 		return Responder{}, nil
 	}
@@ -107,7 +183,7 @@ func makeDataRequest(target, method string, data *Payload) (Responder, error) {
 
 func makeWhoIsRequest(target string) error {
 
-	client, ctx, can, err := rpc.GetCabalRequest(g.opts.standalone.CoreAddress, time.Second)
+	client, ctx, can, err := rpc.GetCabalRequest(g.coreAddress(), time.Second)
 	defer can()
 	if err != nil {
 		return err
@@ -126,10 +202,23 @@ func makeWhoIsRequest(target string) error {
 		return err
 	}
 
+	// core only lets the request through itself, which is remembered like an address so
+	// that it is not asked again for each request
+	if reply.GetError() == "whois.viaCore" {
+		g.mu.Lock()
+		g.whoIs[target] = ""
+		g.whoIsTokens[target] = ""
+		g.mu.Unlock()
+		return nil
+	}
+
 	if reply.GetError() != "" {
 		return fmt.Errorf(reply.GetError())
 	}
 
-	g.whoIs[target] = reply.TargetAddress
+	g.mu.Lock()
+	g.whoIs[target] = reply.GetTargetAddress()
+	g.whoIsTokens[target] = reply.GetToken()
+	g.mu.Unlock()
 	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gmbh-micro/rpc"
 	"github.com/gmbh-micro/rpc/intrigue"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
@@ -67,21 +68,58 @@ func (s *_server) UpdateRegistration(ctx context.Context, in *intrigue.ServiceUp
 				g.connect()
 			}()
 		}
+	} else if request == "core.drain" || request == "core.undrain" {
+		// core signs the notice with the fingerprint it gave the service
+		md, _ := metadata.FromIncomingContext(ctx)
+		token := strings.Join(md.Get("token"), "")
+		if !rpc.VerifySender(g.getReg().fingerprint, request, token) {
+			print("could not verify %s notice", request)
+			return &intrigue.Receipt{Error: "sender.unverified"}, nil
+		}
+		g.mu.Lock()
+		g.draining = request == "core.drain"
+		g.mu.Unlock()
+		return &intrigue.Receipt{Message: "ack"}, nil
 	}
 	return &intrigue.Receipt{Error: "unknown.request"}, nil
 }
 
 func (s *_server) Data(ctx context.Context, in *intrigue.DataRequest) (*intrigue.DataResponse, error) {
 
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || in.GetRequest().GetTport() == nil {
+		print("could not get metadata from data request")
+		return &intrigue.DataResponse{Error: "invalid request"}, nil
+	}
+
+	sender := strings.Join(md.Get("sender"), "")
+	token := strings.Join(md.Get("token"), "")
+	if !rpc.VerifySender(g.getReg().fingerprint, sender, token) {
+		print("could not verify sender of data request; sender=%s", sender)
+		return &intrigue.DataResponse{Error: "sender.unverified"}, nil
+	}
+
+	// core stops forwarding to a service that is not ready, peers that already know the
+	// address are told so here
+	if !g.isReady() {
+		return &intrigue.DataResponse{Error: "service.notReady"}, nil
+	}
+	if g.isDraining() {
+		return &intrigue.DataResponse{Error: "service.draining"}, nil
+	}
+
+	// handlers only ever see the verified sender
+	in.Request.Tport.Sender = sender
+
 	mcs := strconv.Itoa(g.msgCounter)
 	g.msgCounter++
 	if g.env != "C" || os.Getenv("LOGGING") == "1" {
-		print("=="+mcs+"==> from=%s; method=%s", in.GetRequest().GetTport().GetSender(), in.GetRequest().GetTport().GetMethod())
+		print("=="+mcs+"==> from=%s; method=%s", sender, in.GetRequest().GetTport().GetMethod())
 	}
 
-	responder, err := handleDataRequest(*in.GetRequest())
+	responder, err := handleDataRequest(ctx, *in.GetRequest())
 	if err != nil {
-		panic(err)
+		return &intrigue.DataResponse{Error: err.Error()}, nil
 	}
 	return &intrigue.DataResponse{Responder: responder}, nil
 }
@@ -111,6 +149,7 @@ func (s *_server) Summary(ctx context.Context, in *intrigue.Action) (*intrigue.S
 				PeerGroups: g.opts.service.PeerGroups,
 				ParentID:   g.parentID,
 				Errors:     []string{},
+				Metrics:    g.summaryMetrics(),
 			},
 		},
 	}
@@ -122,5 +161,30 @@ func (s *_server) WhoIs(ctx context.Context, in *intrigue.WhoIsRequest) (*intrig
 	return &intrigue.WhoIsResponse{Error: "unsupported in client"}, nil
 }
 func (s *_server) Alive(ctx context.Context, ping *intrigue.Ping) (*intrigue.Pong, error) {
-	return &intrigue.Pong{Time: time.Now().Format(time.Stamp)}, nil
+	return &intrigue.Pong{
+		Time:   time.Now().Format(time.Stamp),
+		Health: g.checkHealth(ctx).proto(),
+	}, nil
+}
+
+// summaryMetrics returns the pool and compression counters for the summary
+func (g *Client) summaryMetrics() map[string]int64 {
+	ret := g.poolMetrics()
+	for k, v := range g.compression.Snapshot() {
+		ret[k] = v
+	}
+	return ret
+}
+
+func (s *_server) EvictService(ctx context.Context, in *intrigue.Action) (*intrigue.Receipt, error) {
+	return &intrigue.Receipt{Error: "unsupported in client"}, nil
+}
+func (s *_server) DrainService(ctx context.Context, in *intrigue.Action) (*intrigue.Receipt, error) {
+	return &intrigue.Receipt{Error: "unsupported in client"}, nil
+}
+func (s *_server) UndrainService(ctx context.Context, in *intrigue.Action) (*intrigue.Receipt, error) {
+	return &intrigue.Receipt{Error: "unsupported in client"}, nil
+}
+func (s *_server) InspectService(ctx context.Context, in *intrigue.Action) (*intrigue.InspectReceipt, error) {
+	return &intrigue.InspectReceipt{Error: "unsupported in client"}, nil
 }
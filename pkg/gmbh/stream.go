@@ -0,0 +1,214 @@
+package gmbh
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gmbh-micro/rpc"
+	"github.com/gmbh-micro/rpc/intrigue"
+	"google.golang.org/grpc/metadata"
+)
+
+/**********************************************************************************
+**** Streaming Data Requests
+**********************************************************************************/
+
+// maxChunkSize is the largest piece of data sent in one message of a stream, well below
+// the default grpc message limit
+const maxChunkSize = 64 * 1024
+
+// StreamHandlerFunc handles a stream opened by another service with OpenStream. The handler
+// reads what the caller sends until io.EOF and writes its response to the stream. The stream
+// is closed when the handler returns; a returned error is passed on to the caller.
+type StreamHandlerFunc = func(req Request, stream *Stream) error
+
+// RouteStream registers a handler for streams opened to route
+func (g *Client) RouteStream(route string, handler StreamHandlerFunc) {
+	g.registeredStreams[route] = handler
+}
+
+// chunkStream is implemented by both ends of the grpc DataStream
+type chunkStream interface {
+	Send(*intrigue.StreamChunk) error
+	Recv() (*intrigue.StreamChunk, error)
+}
+
+// Stream is a streaming data request between two services through gmbh. It implements
+// io.ReadWriteCloser so that large payloads and files can be copied into and out of it
+// without having to fit into a single message.
+type Stream struct {
+	chunks chunkStream
+
+	// closeSend and cancel are only set on the side that opened the stream
+	closeSend func() error
+	cancel    context.CancelFunc
+
+	// buf holds what is left of the last chunk received
+	buf []byte
+
+	// err is returned by Read once buf is empty
+	err error
+}
+
+// OpenStream opens a stream to method of target through gmbh. Once everything has been
+// written, call CloseWrite and read the response until io.EOF, then Close the stream.
+func (g *Client) OpenStream(target, method string) (*Stream, error) {
+
+	client, con, err := rpc.DialCabal(g.coreAddress())
+	if err != nil {
+		return nil, errors.New("data.gmbhUnavailable")
+	}
+
+	ctx, can := context.WithCancel(context.Background())
+	ctx = metadata.AppendToOutgoingContext(
+		ctx,
+		"sender", g.opts.service.Name,
+		"fingerprint", g.getReg().fingerprint,
+	)
+	cancel := func() {
+		can()
+		con.Close()
+	}
+
+	s, err := client.DataStream(ctx)
+	if err == nil {
+		err = s.Send(&intrigue.StreamChunk{
+			Tport: &intrigue.Transport{
+				Target: target,
+				Method: method,
+				Sender: g.opts.service.Name,
+			},
+		})
+	}
+	if err != nil {
+		cancel()
+		return nil, errors.New("could not open stream: " + err.Error())
+	}
+
+	return &Stream{
+		chunks:    s,
+		closeSend: s.CloseSend,
+		cancel:    cancel,
+	}, nil
+}
+
+// Read reads the data sent by the other side of the stream. io.EOF is returned once the other
+// side is done, or the error that it returned.
+func (s *Stream) Read(p []byte) (int, error) {
+	for len(s.buf) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+		chunk, err := s.chunks.Recv()
+		if err != nil {
+			s.err = err
+			continue
+		}
+		if chunk.GetError() != "" {
+			s.err = errors.New(chunk.GetError())
+			continue
+		}
+		s.buf = chunk.GetData()
+	}
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
+
+// Write sends p to the other side of the stream in chunks of up to 64KB
+func (s *Stream) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		end := written + maxChunkSize
+		if end > len(p) {
+			end = len(p)
+		}
+		err := s.chunks.Send(&intrigue.StreamChunk{Data: p[written:end]})
+		if err != nil {
+			return written, err
+		}
+		written = end
+	}
+	return written, nil
+}
+
+// CloseWrite tells the other side that nothing more will be written, after which the response
+// can still be read. It does nothing in a stream handler.
+func (s *Stream) CloseWrite() error {
+	if s.closeSend == nil {
+		return nil
+	}
+	return s.closeSend()
+}
+
+// Close ends the stream. It does nothing in a stream handler.
+func (s *Stream) Close() error {
+	if s.cancel == nil {
+		return nil
+	}
+	err := s.CloseWrite()
+	s.cancel()
+	return err
+}
+
+func handleDataStream(stream intrigue.Cabal_DataStreamServer, first *intrigue.StreamChunk) error {
+
+	request := Request{transport: transportFromProto(first.GetTport())}
+	method := request.transport.Method
+
+	handler, ok := g.registeredStreams[method]
+	if !ok {
+		print("could not find stream hander=%s", method)
+		return stream.Send(&intrigue.StreamChunk{Error: "could not find method in service map"})
+	}
+
+	pool := g.getPool(method)
+	err := pool.acquire(stream.Context())
+	if err != nil {
+		print("no handler available for %s; err=%s", method, err.Error())
+		return stream.Send(&intrigue.StreamChunk{Error: err.Error()})
+	}
+	defer pool.release()
+
+	err = handler(request, &Stream{chunks: stream})
+	if err != nil {
+		return stream.Send(&intrigue.StreamChunk{Error: err.Error()})
+	}
+	return nil
+}
+
+func (s *_server) DataStream(stream intrigue.Cabal_DataStreamServer) error {
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	md, ok := metadata.FromIncomingContext(stream.Context())
+	if !ok || first.GetTport() == nil {
+		print("could not get metadata from data stream")
+		return stream.Send(&intrigue.StreamChunk{Error: "invalid request"})
+	}
+
+	sender := strings.Join(md.Get("sender"), "")
+	token := strings.Join(md.Get("token"), "")
+	if !rpc.VerifySender(g.getReg().fingerprint, sender, token) {
+		print("could not verify sender of data stream; sender=%s", sender)
+		return stream.Send(&intrigue.StreamChunk{Error: "sender.unverified"})
+	}
+
+	// the same as Data, peers that already know the address are turned away here
+	if !g.isReady() {
+		return stream.Send(&intrigue.StreamChunk{Error: "service.notReady"})
+	}
+	if g.isDraining() {
+		return stream.Send(&intrigue.StreamChunk{Error: "service.draining"})
+	}
+
+	// handlers only ever see the verified sender
+	first.Tport.Sender = sender
+
+	print("==stream==> from=%s; method=%s", sender, first.GetTport().GetMethod())
+	return handleDataStream(stream, first)
+}
@@ -20,9 +20,17 @@ echo "installing deps"
 go get github.com/golang/protobuf/proto
 go get github.com/golang/protobuf/protoc-gen-go 
 go get google.golang.org/grpc
+go get github.com/BurntSushi/toml
 go get github.com/fatih/color
-go get gopkg.in/yaml.v2
 go get github.com/rs/xid
+go get github.com/vmihailenco/msgpack
+go get github.com/klauspost/compress
+go get github.com/gorilla/websocket
+go get github.com/nsf/termbox-go
+go get gopkg.in/yaml.v2
+go get github.com/hashicorp/raft
+go get github.com/hashicorp/raft-boltdb
+go get github.com/miekg/dns
 
 ## Build Binaries
 echo "building gmbh"
@@ -31,6 +39,8 @@ echo "building gmbhCore"
 go build -v -o ./bin/gmbhCore ./cmd/gmbhCore/*.go
 echo "building gmbhProcm"
 go build -v -o ./bin/gmbhProcm ./cmd/gmbhProcm/*.go
+echo "building gmbhGateway"
+go build -v -o ./bin/gmbhGateway ./cmd/gmbhGateway/*.go
 
 ## Copy to bin
 echo "copying files to $GOPATH/bin"